@@ -0,0 +1,94 @@
+package sqlc_dataloader
+
+import (
+	"context"
+	"fmt"
+)
+
+// TableInfo is the metadata of a generated table loader.
+type TableInfo struct {
+	// Table is the full name of the table, e.g. "public.authors".
+	Table string
+	// KeyColumn is the name of the key column, e.g. "id".
+	KeyColumn string
+	// KeyType is the Go type of the key, e.g. "pgtype.UUID".
+	KeyType string
+}
+
+// AnyLoader is a table loader with the erased types of the keys and the rows.
+// It lets the generic tools, e.g. admin panels, load rows by the table name.
+type AnyLoader interface {
+	// Info returns the metadata of the table.
+	Info() TableInfo
+	// LoadAny loads the row by the key. The key must have the key type of the table.
+	LoadAny(ctx context.Context, key any) (any, error)
+	// LoadManyAny loads the rows by the keys. The keys must have the key type of the table.
+	LoadManyAny(ctx context.Context, keys []any) ([]any, []error)
+}
+
+// TypedLoader is the part of a generated loader used by AnyLoader.
+type TypedLoader[K any, V any] interface {
+	Load(ctx context.Context, key K) (V, error)
+	LoadMany(ctx context.Context, keys []K) ([]V, []error)
+}
+
+// KeyTypeError is returned by AnyLoader if the key has another type than the key of the table.
+type KeyTypeError struct {
+	Info TableInfo
+	Key  any
+}
+
+func (e *KeyTypeError) Error() string {
+	return fmt.Sprintf("the key of %s must be %s, got %T", e.Info.Table, e.Info.KeyType, e.Key)
+}
+
+type anyLoader[K any, V any] struct {
+	info   TableInfo
+	loader TypedLoader[K, V]
+}
+
+// NewAnyLoader wraps the typed loader to AnyLoader.
+func NewAnyLoader[K any, V any](info TableInfo, loader TypedLoader[K, V]) AnyLoader {
+	return &anyLoader[K, V]{
+		info:   info,
+		loader: loader,
+	}
+}
+
+func (l *anyLoader[K, V]) Info() TableInfo {
+	return l.info
+}
+
+func (l *anyLoader[K, V]) LoadAny(ctx context.Context, key any) (any, error) {
+	typedKey, ok := key.(K)
+	if !ok {
+		return nil, &KeyTypeError{Info: l.info, Key: key}
+	}
+	return l.loader.Load(ctx, typedKey)
+}
+
+func (l *anyLoader[K, V]) LoadManyAny(ctx context.Context, keys []any) ([]any, []error) {
+	typedKeys := make([]K, len(keys))
+	var errs []error
+	for i, key := range keys {
+		typedKey, ok := key.(K)
+		if !ok {
+			if errs == nil {
+				errs = make([]error, len(keys))
+			}
+			errs[i] = &KeyTypeError{Info: l.info, Key: key}
+			continue
+		}
+		typedKeys[i] = typedKey
+	}
+	if errs != nil {
+		return make([]any, len(keys)), errs
+	}
+
+	items, loadErrs := l.loader.LoadMany(ctx, typedKeys)
+	result := make([]any, len(items))
+	for i, item := range items {
+		result[i] = item
+	}
+	return result, loadErrs
+}
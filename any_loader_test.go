@@ -0,0 +1,70 @@
+package sqlc_dataloader_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	dl "github.com/debugger84/sqlc-dataloader"
+	"github.com/stretchr/testify/require"
+)
+
+type user struct {
+	ID   int
+	Name string
+}
+
+type userLoader map[int]user
+
+func (l userLoader) Load(_ context.Context, key int) (user, error) {
+	if u, ok := l[key]; ok {
+		return u, nil
+	}
+	return user{}, dl.ErrNoRows
+}
+
+func (l userLoader) LoadMany(ctx context.Context, keys []int) ([]user, []error) {
+	users := make([]user, len(keys))
+	var errs []error
+	for i, key := range keys {
+		u, err := l.Load(ctx, key)
+		if err != nil {
+			if errs == nil {
+				errs = make([]error, len(keys))
+			}
+			errs[i] = err
+		}
+		users[i] = u
+	}
+	return users, errs
+}
+
+func TestAnyLoader(t *testing.T) {
+	info := dl.TableInfo{Table: "public.users", KeyColumn: "id", KeyType: "int"}
+	loader := dl.NewAnyLoader[int, user](info, userLoader{1: {ID: 1, Name: "John"}})
+	ctx := context.Background()
+
+	require.Equal(t, info, loader.Info())
+
+	item, err := loader.LoadAny(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, user{ID: 1, Name: "John"}, item)
+
+	_, err = loader.LoadAny(ctx, 2)
+	require.ErrorIs(t, err, dl.ErrNoRows)
+
+	_, err = loader.LoadAny(ctx, "1")
+	var keyErr *dl.KeyTypeError
+	require.True(t, errors.As(err, &keyErr))
+	require.Equal(t, "the key of public.users must be int, got string", err.Error())
+
+	items, errs := loader.LoadManyAny(ctx, []any{1, 2})
+	require.Equal(t, []any{user{ID: 1, Name: "John"}, user{}}, items)
+	require.Len(t, errs, 2)
+	require.NoError(t, errs[0])
+	require.ErrorIs(t, errs[1], dl.ErrNoRows)
+
+	_, errs = loader.LoadManyAny(ctx, []any{1, int64(2)})
+	require.NoError(t, errs[0])
+	require.True(t, errors.As(errs[1], &keyErr))
+}
@@ -0,0 +1,80 @@
+package sqlc_dataloader
+
+import (
+	"context"
+
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+// CountLoaderConfig is the configuration of CountLoader.
+type CountLoaderConfig[K comparable] struct {
+	// Table is the name of the counted table in the format schema.tablename. It is used in the retry events.
+	Table string
+	// Query selects the keys and the numbers of their rows by the keys passed as the only argument,
+	// e.g. "SELECT author_id, count(*) FROM books WHERE author_id = ANY($1) GROUP BY author_id".
+	Query string
+	// DB runs the query.
+	DB QueryFunc
+	// Args returns the query argument of the keys. The keys are passed as is if it is nil.
+	Args func(keys []K) any
+	// Cache is the cache of the loaded numbers. The numbers are not cached if it is nil.
+	Cache dataloader.Cache[K, int64]
+	LoaderSettings
+}
+
+// CountLoader batches the requests of the numbers of the rows by the keys, e.g. the numbers of the books of the authors.
+// The number of a key without rows is zero.
+type CountLoader[K comparable] struct {
+	loader *TableLoader[K, int64]
+}
+
+// NewCountLoader creates the loader. The options override the settings of the config.
+func NewCountLoader[K comparable](config CountLoaderConfig[K], options ...LoaderOption) *CountLoader[K] {
+	l := newTableLoader(
+		TableLoaderConfig[K, int64]{
+			Table:          config.Table,
+			Query:          config.Query,
+			DB:             config.DB,
+			Args:           config.Args,
+			Cache:          config.Cache,
+			LoaderSettings: config.LoaderSettings,
+		},
+		options...,
+	)
+	l.collect = func(ctx context.Context, rows Rows, keys []K) (*fetchedItems[K, int64], error) {
+		fetched := &fetchedItems[K, int64]{items: make(map[K]int64, len(keys))}
+		for rows.Next() {
+			var key K
+			var count int64
+			if err := rows.Scan(&key, &count); err != nil {
+				return nil, err
+			}
+			fetched.items[key] += count
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return fetched, nil
+	}
+	return &CountLoader[K]{loader: l}
+}
+
+// Load loads the number of the rows of the key.
+func (l *CountLoader[K]) Load(ctx context.Context, key K) (int64, error) {
+	return l.loader.Load(ctx, key)
+}
+
+// LoadMany loads the numbers of the rows of the keys. The errors are nil if all numbers are loaded.
+func (l *CountLoader[K]) LoadMany(ctx context.Context, keys []K) ([]int64, []error) {
+	return l.loader.LoadMany(ctx, keys)
+}
+
+// Clear removes the number from the cache.
+func (l *CountLoader[K]) Clear(ctx context.Context, key K) {
+	l.loader.Clear(ctx, key)
+}
+
+// Prime adds the number to the cache if there is no number with the key.
+func (l *CountLoader[K]) Prime(ctx context.Context, key K, count int64) {
+	l.loader.Prime(ctx, key, count)
+}
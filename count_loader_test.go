@@ -0,0 +1,52 @@
+package sqlc_dataloader_test
+
+import (
+	"context"
+	"testing"
+
+	dl "github.com/debugger84/sqlc-dataloader"
+	"github.com/stretchr/testify/require"
+)
+
+// countRows are the rows of the keys and the numbers of their rows.
+type countRows struct {
+	counts [][2]int
+	next   int
+}
+
+func (r *countRows) Next() bool {
+	r.next++
+	return r.next <= len(r.counts)
+}
+
+func (r *countRows) Scan(dest ...any) error {
+	*dest[0].(*int) = r.counts[r.next-1][0]
+	*dest[1].(*int64) = int64(r.counts[r.next-1][1])
+	return nil
+}
+
+func (r *countRows) Err() error {
+	return nil
+}
+
+func (r *countRows) Close() {}
+
+func TestCountLoader_LoadMany(t *testing.T) {
+	var queried []int
+	loader := dl.NewCountLoader(
+		dl.CountLoaderConfig[int]{
+			Table: "public.books",
+			Query: "SELECT author_id, count(*) FROM books WHERE author_id = ANY($1) GROUP BY author_id",
+			DB: func(_ context.Context, _ string, args ...any) (dl.Rows, error) {
+				queried = args[0].([]int)
+				return &countRows{counts: [][2]int{{1, 3}, {2, 1}}}, nil
+			},
+		},
+	)
+
+	counts, errs := loader.LoadMany(context.Background(), []int{2, 3, 1})
+
+	require.Nil(t, errs)
+	require.Equal(t, []int64{1, 0, 3}, counts, "the number of a key without rows is zero")
+	require.ElementsMatch(t, []int{1, 2, 3}, queried)
+}
@@ -1,5 +1,39 @@
 package sqlc_dataloader
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
 
 var ErrNoRows = errors.New("no rows in result set")
+
+// QueryTimeoutError is returned for the keys of a query that has not finished in the query timeout of the loader.
+// It matches context.DeadlineExceeded in errors.Is, but not ErrNoRows.
+type QueryTimeoutError struct {
+	Timeout time.Duration
+	// Err is the error returned by the driver.
+	Err error
+}
+
+func (e *QueryTimeoutError) Error() string {
+	return fmt.Sprintf("the query exceeded the timeout of %s: %s", e.Timeout, e.Err)
+}
+
+func (e *QueryTimeoutError) Unwrap() []error {
+	return []error{context.DeadlineExceeded, e.Err}
+}
+
+// DuplicateKeyError is returned for the key with more than one row if the DuplicateKeys setting of the loader is DuplicateKeysError.
+// It means that the key column of the loader is not unique.
+type DuplicateKeyError struct {
+	Table string
+	Key   any
+	// Rows is the number of the rows of the key.
+	Rows int
+}
+
+func (e *DuplicateKeyError) Error() string {
+	return fmt.Sprintf("the key %v of %s has %d rows, expected one", e.Key, e.Table, e.Rows)
+}
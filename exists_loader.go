@@ -0,0 +1,112 @@
+package sqlc_dataloader
+
+import (
+	"context"
+	"errors"
+
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+// newExistsLoader creates the loader checking the keys by the ExistsQuery of the config.
+// The keys without rows get false.
+func newExistsLoader[K comparable, V any](config TableLoaderConfig[K, V]) *TableLoader[K, bool] {
+	scanKey := config.scanKey
+	if scanKey == nil {
+		scanKey = func(row Scanner) (K, error) {
+			var key K
+			err := row.Scan(&key)
+			return key, err
+		}
+	}
+	l := newTableLoader(
+		TableLoaderConfig[K, bool]{
+			Table:          config.Table,
+			Query:          config.ExistsQuery,
+			DB:             config.DB,
+			Args:           config.Args,
+			Cache:          config.ExistsCache,
+			LoaderSettings: config.LoaderSettings,
+		},
+	)
+	l.collect = func(ctx context.Context, rows Rows, keys []K) (*fetchedItems[K, bool], error) {
+		fetched := &fetchedItems[K, bool]{items: make(map[K]bool, len(keys))}
+		for rows.Next() {
+			key, err := scanKey(rows)
+			if err != nil {
+				return nil, err
+			}
+			fetched.items[key] = true
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return fetched, nil
+	}
+	return l
+}
+
+// markExisting sets the cached result of Exists of the key to true.
+// The cached false is replaced, because Prime keeps the cached results.
+// Nothing is done if the results of Exists are not cached.
+func (l *TableLoader[K, V]) markExisting(ctx context.Context, key K) {
+	if l.exists == nil {
+		return
+	}
+	switch l.config.ExistsCache.(type) {
+	case nil, *dataloader.NoCache[K, bool]:
+		return
+	}
+	l.exists.Clear(ctx, key)
+	l.exists.Prime(ctx, key, true)
+}
+
+// Exists checks if there is a row with the key. Only the keys are selected by the ExistsQuery of the config,
+// the full row is loaded if the query is not set.
+func (l *TableLoader[K, V]) Exists(ctx context.Context, key K) (bool, error) {
+	if l.exists == nil {
+		_, err := l.Load(ctx, key)
+		if errors.Is(err, ErrNoRows) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+	return l.exists.Load(ctx, key)
+}
+
+// ExistsMany checks if there are the rows with the keys. The errors are nil if all keys are checked.
+func (l *TableLoader[K, V]) ExistsMany(ctx context.Context, keys []K) ([]bool, []error) {
+	if l.exists != nil {
+		return l.exists.LoadMany(ctx, keys)
+	}
+	_, loadErrs := l.LoadMany(ctx, keys)
+	exists := make([]bool, len(keys))
+	var errs []error
+	for i := range keys {
+		if loadErrs == nil || loadErrs[i] == nil {
+			exists[i] = true
+			continue
+		}
+		if errors.Is(loadErrs[i], ErrNoRows) {
+			continue
+		}
+		if errs == nil {
+			errs = make([]error, len(keys))
+		}
+		errs[i] = loadErrs[i]
+	}
+	return exists, errs
+}
+
+// Exists checks if there is a row with the key.
+func (l *KeyedLoader[P, E, V]) Exists(ctx context.Context, key P) (bool, error) {
+	return l.loader.Exists(ctx, l.adapter.Encode(key))
+}
+
+// ExistsMany checks if there are the rows with the keys. The errors are nil if all keys are checked.
+func (l *KeyedLoader[P, E, V]) ExistsMany(ctx context.Context, keys []P) ([]bool, []error) {
+	encoded := make([]E, len(keys))
+	for i, key := range keys {
+		encoded[i] = l.adapter.Encode(key)
+	}
+	return l.loader.ExistsMany(ctx, encoded)
+}
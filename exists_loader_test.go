@@ -0,0 +1,197 @@
+package sqlc_dataloader_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	dl "github.com/debugger84/sqlc-dataloader"
+	"github.com/graph-gophers/dataloader/v7"
+	"github.com/stretchr/testify/require"
+)
+
+// keyRows are the rows of the keys selected by the exists query.
+type keyRows struct {
+	keys []int
+	next int
+}
+
+func (r *keyRows) Next() bool {
+	r.next++
+	return r.next <= len(r.keys)
+}
+
+func (r *keyRows) Scan(dest ...any) error {
+	*dest[0].(*int) = r.keys[r.next-1]
+	return nil
+}
+
+func (r *keyRows) Err() error {
+	return nil
+}
+
+func (r *keyRows) Close() {}
+
+const existsQuery = "SELECT id FROM users WHERE id = ANY($1)"
+
+// newExistsLoader creates the loader of the users with the exists query of the ids of db.users.
+// The exists queries are recorded to existsQueries.
+func newExistsLoader(db *fakeDB, existsQueries *[][]int) *dl.TableLoader[int, user] {
+	return dl.NewTableLoader(
+		dl.TableLoaderConfig[int, user]{
+			Table: "public.users",
+			Query: "SELECT id, name FROM users WHERE id = ANY($1)",
+			DB: func(ctx context.Context, query string, args ...any) (dl.Rows, error) {
+				if query != existsQuery {
+					return db.Query(ctx, query, args...)
+				}
+				keys := args[0].([]int)
+				*existsQueries = append(*existsQueries, keys)
+				rows := &keyRows{}
+				for _, u := range db.users {
+					for _, key := range keys {
+						if u.ID == key {
+							rows.keys = append(rows.keys, key)
+						}
+					}
+				}
+				return rows, nil
+			},
+			Scan: func(row dl.Scanner) (user, error) {
+				var u user
+				err := row.Scan(&u.ID, &u.Name)
+				return u, err
+			},
+			Key: func(u user) int {
+				return u.ID
+			},
+			ExistsQuery: existsQuery,
+			ExistsCache: dataloader.NewCache[int, bool](),
+		},
+	)
+}
+
+func TestTableLoader_ExistsMany(t *testing.T) {
+	db := &fakeDB{users: []user{{ID: 1, Name: "John"}, {ID: 3, Name: "Jane"}}}
+	var existsQueries [][]int
+	loader := newExistsLoader(db, &existsQueries)
+
+	exists, errs := loader.ExistsMany(context.Background(), []int{1, 2, 3})
+
+	require.Nil(t, errs)
+	require.Equal(t, []bool{true, false, true}, exists)
+	require.Len(t, existsQueries, 1)
+	require.ElementsMatch(t, []int{1, 2, 3}, existsQueries[0])
+	require.Empty(t, db.queries, "the full rows are not loaded")
+
+	exists, errs = loader.ExistsMany(context.Background(), []int{3, 2})
+
+	require.Nil(t, errs)
+	require.Equal(t, []bool{true, false}, exists)
+	require.Len(t, existsQueries, 1, "the results are cached")
+}
+
+func TestTableLoader_Exists_AfterLoad(t *testing.T) {
+	db := &fakeDB{users: []user{{ID: 1, Name: "John"}}}
+	var existsQueries [][]int
+	loader := newExistsLoader(db, &existsQueries)
+	ctx := context.Background()
+
+	_, err := loader.Load(ctx, 1)
+	require.NoError(t, err)
+	exists, err := loader.Exists(ctx, 1)
+
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Empty(t, existsQueries, "the loaded key is cached as existing")
+
+	loader.Clear(ctx, 1)
+	exists, err = loader.Exists(ctx, 1)
+
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Len(t, existsQueries, 1, "the key is checked again after Clear")
+}
+
+func TestTableLoader_Exists_WithoutQuery(t *testing.T) {
+	db := &fakeDB{users: []user{{ID: 1, Name: "John"}}}
+	loader := newUserTableLoader(db)
+
+	exists, errs := loader.ExistsMany(context.Background(), []int{1, 2})
+
+	require.Nil(t, errs)
+	require.Equal(t, []bool{true, false}, exists)
+	require.Len(t, db.queries, 1, "the full rows are loaded")
+}
+
+func TestTableLoader_Exists_LoadAfterInsert(t *testing.T) {
+	db := &fakeDB{}
+	var existsQueries [][]int
+	loader := newExistsLoader(db, &existsQueries)
+	ctx := context.Background()
+
+	exists, err := loader.Exists(ctx, 1)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	db.users = append(db.users, user{ID: 1, Name: "John"})
+	_, err = loader.Load(ctx, 1)
+	require.NoError(t, err)
+	exists, err = loader.Exists(ctx, 1)
+
+	require.NoError(t, err)
+	require.True(t, exists, "the loaded row replaces the cached false")
+	require.Len(t, existsQueries, 1)
+}
+
+// nameRows are the rows of the names selected by the exists query.
+type nameRows struct {
+	names []string
+	next  int
+}
+
+func (r *nameRows) Next() bool {
+	r.next++
+	return r.next <= len(r.names)
+}
+
+func (r *nameRows) Scan(dest ...any) error {
+	*dest[0].(*string) = r.names[r.next-1]
+	return nil
+}
+
+func (r *nameRows) Err() error {
+	return nil
+}
+
+func (r *nameRows) Close() {}
+
+func TestKeyedLoader_ExistsMany_Normalized(t *testing.T) {
+	stored := []string{"John", "Jane"}
+	loader := dl.NewKeyedLoader(
+		dl.TableLoaderConfig[string, user]{
+			Query: "SELECT id, name FROM users WHERE lower(name) = ANY($1)",
+			DB: func(_ context.Context, _ string, args ...any) (dl.Rows, error) {
+				rows := &nameRows{}
+				for _, name := range stored {
+					for _, key := range args[0].([]string) {
+						if strings.ToLower(name) == key {
+							rows.names = append(rows.names, name)
+						}
+					}
+				}
+				return rows, nil
+			},
+			Key: func(u user) string {
+				return dl.LowerKey(u.Name)
+			},
+			ExistsQuery: "SELECT name FROM users WHERE lower(name) = ANY($1)",
+		},
+		dl.NormalizedKeyAdapter(dl.LowerKey),
+	)
+
+	exists, errs := loader.ExistsMany(context.Background(), []string{"JOHN", "jim", "jane"})
+
+	require.Nil(t, errs)
+	require.Equal(t, []bool{true, false, true}, exists, "the scanned keys are normalised like the requested ones")
+}
@@ -0,0 +1,81 @@
+package sqlc_dataloader
+
+import (
+	"context"
+
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+// GroupLoaderConfig is the configuration of GroupLoader.
+type GroupLoaderConfig[K comparable, V any] struct {
+	// Table is the name of the loaded relation, e.g. "public.post_tags". It is used in the retry events.
+	Table string
+	// Query selects the rows by the keys passed as the only argument,
+	// e.g. "SELECT j.post_id, t.id, t.name FROM tags AS t JOIN post_tags AS j ON j.tag_id = t.id WHERE j.post_id = ANY($1)".
+	Query string
+	// DB runs the query.
+	DB QueryFunc
+	// Scan scans the row to the key of the group and the item.
+	Scan func(row Scanner) (K, V, error)
+	// Args returns the query argument of the keys. The keys are passed as is if it is nil.
+	Args func(keys []K) any
+	// Cache is the cache of the loaded groups. The groups are not cached if it is nil.
+	Cache dataloader.Cache[K, []V]
+	LoaderSettings
+}
+
+// GroupLoader batches the requests of the groups of rows by the keys, e.g. the tags of the posts.
+// The items of a group are in the order of the query result, the group of a key without rows is nil.
+type GroupLoader[K comparable, V any] struct {
+	loader *TableLoader[K, []V]
+}
+
+// NewGroupLoader creates the loader. The options override the settings of the config.
+func NewGroupLoader[K comparable, V any](config GroupLoaderConfig[K, V], options ...LoaderOption) *GroupLoader[K, V] {
+	l := newTableLoader(
+		TableLoaderConfig[K, []V]{
+			Table:          config.Table,
+			Query:          config.Query,
+			DB:             config.DB,
+			Args:           config.Args,
+			Cache:          config.Cache,
+			LoaderSettings: config.LoaderSettings,
+		},
+		options...,
+	)
+	l.collect = func(ctx context.Context, rows Rows, keys []K) (*fetchedItems[K, []V], error) {
+		fetched := &fetchedItems[K, []V]{items: make(map[K][]V, len(keys))}
+		for rows.Next() {
+			key, item, err := config.Scan(rows)
+			if err != nil {
+				return nil, err
+			}
+			fetched.items[key] = append(fetched.items[key], item)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return fetched, nil
+	}
+	return &GroupLoader[K, V]{loader: l}
+}
+
+// Load loads the group of the key.
+func (l *GroupLoader[K, V]) Load(ctx context.Context, key K) ([]V, error) {
+	return l.loader.Load(ctx, key)
+}
+
+// LoadMany loads the groups of the keys. The errors are nil if all groups are loaded.
+func (l *GroupLoader[K, V]) LoadMany(ctx context.Context, keys []K) ([][]V, []error) {
+	return l.loader.LoadMany(ctx, keys)
+}
+
+// Clear removes the group from the cache.
+func (l *GroupLoader[K, V]) Clear(ctx context.Context, key K) {
+	l.loader.Clear(ctx, key)
+}
+
+// Prime adds the group to the cache if there is no group with the key.
+func (l *GroupLoader[K, V]) Prime(ctx context.Context, key K, group []V) {
+	l.loader.Prime(ctx, key, group)
+}
@@ -0,0 +1,57 @@
+package sqlc_dataloader_test
+
+import (
+	"context"
+	"testing"
+
+	dl "github.com/debugger84/sqlc-dataloader"
+	"github.com/stretchr/testify/require"
+)
+
+// newNamesLoader creates the loader of the names of the users grouped by the user ids.
+func newNamesLoader(db *fakeDB, options ...dl.LoaderOption) *dl.GroupLoader[int, string] {
+	config := dl.GroupLoaderConfig[int, string]{
+		Table: "public.user_names",
+		Query: "SELECT id, name FROM user_names WHERE id = ANY($1)",
+		DB:    db.Query,
+		Scan: func(row dl.Scanner) (int, string, error) {
+			var id int
+			var name string
+			err := row.Scan(&id, &name)
+			return id, name, err
+		},
+	}
+	return dl.NewGroupLoader(config, options...)
+}
+
+func TestGroupLoader_LoadMany(t *testing.T) {
+	db := &fakeDB{
+		users: []user{{ID: 1, Name: "john"}, {ID: 2, Name: "jane"}, {ID: 1, Name: "johnny"}},
+	}
+	loader := newNamesLoader(db)
+
+	groups, errs := loader.LoadMany(context.Background(), []int{1, 3, 2})
+
+	require.Nil(t, errs)
+	require.Equal(t, [][]string{{"john", "johnny"}, nil, {"jane"}}, groups, "the group of a key without rows is empty")
+	require.Len(t, db.queries, 1)
+}
+
+func TestGroupLoader_MaxKeysPerQuery(t *testing.T) {
+	db := &fakeDB{
+		users:   []user{{ID: 1, Name: "john"}, {ID: 2, Name: "jane"}, {ID: 3, Name: "jim"}},
+		failKey: 3,
+	}
+	loader := newNamesLoader(db, func(settings *dl.LoaderSettings) {
+		settings.MaxKeysPerQuery = 1
+	})
+
+	groups, errs := loader.LoadMany(context.Background(), []int{1, 2, 3})
+
+	require.Equal(t, [][]string{{"john"}, {"jane"}, nil}, groups)
+	require.Len(t, errs, 3)
+	require.NoError(t, errs[0])
+	require.NoError(t, errs[1])
+	require.EqualError(t, errs[2], "query failed", "the error of a chunk is returned only for its keys")
+	require.Len(t, db.queries, 3)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/debugger84/sqlc-dataloader/internal/opts"
 	"github.com/debugger84/sqlc-dataloader/internal/renderer"
 	"github.com/debugger84/sqlc-dataloader/internal/sqltype"
+	"github.com/debugger84/sqlc-dataloader/internal/verify"
 	"github.com/sqlc-dev/plugin-sdk-go/plugin"
 	"strings"
 )
@@ -17,7 +18,7 @@ func Generate(ctx context.Context, req *plugin.GenerateRequest) (*plugin.Generat
 		return nil, err
 	}
 
-	if err := opts.ValidateOpts(options); err != nil {
+	if err := opts.ValidateOpts(options, req); err != nil {
 		return nil, err
 	}
 
@@ -34,7 +35,10 @@ func Generate(ctx context.Context, req *plugin.GenerateRequest) (*plugin.Generat
 
 	importer := imports.NewImportBuilder(options)
 
-	loaderRendered := renderer.NewDataLoaderRenderer(structs, options, importer)
+	loaderRendered, err := renderer.NewDataLoaderRenderer(structs, options, importer)
+	if err != nil {
+		return nil, err
+	}
 
 	files := make([]*plugin.File, 0)
 	loaderFiles, err := loaderRendered.Render()
@@ -43,6 +47,13 @@ func Generate(ctx context.Context, req *plugin.GenerateRequest) (*plugin.Generat
 	}
 	files = append(files, loaderFiles...)
 
+	if options.VerifyOutput {
+		verifier := verify.NewVerifier(structs, options)
+		if err := verifier.Verify(files, loaderRendered.FileTables()); err != nil {
+			return nil, err
+		}
+	}
+
 	return &plugin.GenerateResponse{
 		Files: files,
 	}, nil
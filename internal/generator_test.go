@@ -8,6 +8,8 @@ import (
 	"github.com/gkampitakis/go-snaps/snaps"
 	"github.com/sqlc-dev/plugin-sdk-go/plugin"
 	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -72,6 +74,55 @@ func TestGenerate(t *testing.T) {
 					Size:  10,
 				},
 			}
+			factory.options.EmitExists = true
+			req := factory.GenerateRequest()
+
+			resp, err := golang.Generate(ctx, req)
+
+			t.Log("Given the LRU cache of the authors loader with the existence checks")
+			t.Log("When the generator is called")
+			t.Log("	Then the generator should return a response without an error")
+			require.NoError(t, err)
+			t.Log("	And the response should contain the generated code")
+			require.NotNil(t, resp)
+			require.Len(t, resp.Files, 2)
+			fn1 := strings.Split(resp.Files[0].Name, "/")[1] + ".snap"
+			fn2 := strings.Split(resp.Files[1].Name, "/")[1] + ".snap"
+			snaps.WithConfig(snaps.Ext("/"+fn1)).
+				MatchStandaloneSnapshot(t, string(resp.Files[0].Contents))
+			snaps.WithConfig(snaps.Ext("/"+fn2)).
+				MatchStandaloneSnapshot(t, string(resp.Files[1].Contents))
+		},
+	)
+
+	t.Run(
+		"Loader with changed id", func(t *testing.T) {
+			factory := NewGenReqFactory()
+			factory.options.PrimaryKeysColumns = []string{"authors.name"}
+			req := factory.GenerateRequest()
+
+			resp, err := golang.Generate(ctx, req)
+
+			t.Log("Given the 'select * from authors' SQL query is passed to the generator")
+			t.Log("When the generator is called")
+			t.Log("	Then the generator should return a response without an error")
+			require.NoError(t, err)
+			t.Log("	And the response should contain the generated code")
+			require.NotNil(t, resp)
+			require.Len(t, resp.Files, 2)
+			fn1 := strings.Split(resp.Files[0].Name, "/")[1] + ".snap"
+			fn2 := strings.Split(resp.Files[1].Name, "/")[1] + ".snap"
+			snaps.WithConfig(snaps.Ext("/"+fn1)).
+				MatchStandaloneSnapshot(t, string(resp.Files[0].Contents))
+			snaps.WithConfig(snaps.Ext("/"+fn2)).
+				MatchStandaloneSnapshot(t, string(resp.Files[1].Contents))
+		},
+	)
+
+	t.Run(
+		"Skip loader", func(t *testing.T) {
+			factory := NewGenReqFactory()
+			factory.options.ExcludeTables = []string{"public.authors"}
 			req := factory.GenerateRequest()
 
 			resp, err := golang.Generate(ctx, req)
@@ -80,81 +131,924 @@ func TestGenerate(t *testing.T) {
 			t.Log("When the generator is called")
 			t.Log("	Then the generator should return a response without an error")
 			require.NoError(t, err)
-			t.Log("	And the response should contain the generated code")
+			t.Log("	And the response should contain the generated code")
+			require.NotNil(t, resp)
+			require.Len(t, resp.Files, 0)
+		},
+	)
+
+	t.Run(
+		"Loader with id of enum type", func(t *testing.T) {
+			factory := NewGenReqFactory()
+			factory.options.PrimaryKeysColumns = []string{"authors.status"}
+			req := factory.GenerateRequest()
+
+			resp, err := golang.Generate(ctx, req)
+
+			t.Log("Given the 'select * from authors' SQL query is passed to the generator")
+			t.Log("When the generator is called")
+			t.Log("	Then the generator should return a response without an error")
+			require.NoError(t, err)
+			t.Log("	And the response should contain the generated code")
+			require.NotNil(t, resp)
+			require.Len(t, resp.Files, 2)
+			fn1 := strings.Split(resp.Files[0].Name, "/")[1] + ".snap"
+			fn2 := strings.Split(resp.Files[1].Name, "/")[1] + ".snap"
+			snaps.WithConfig(snaps.Ext("/"+fn1)).
+				MatchStandaloneSnapshot(t, string(resp.Files[0].Contents))
+			snaps.WithConfig(snaps.Ext("/"+fn2)).
+				MatchStandaloneSnapshot(t, string(resp.Files[1].Contents))
+		},
+	)
+
+	t.Run(
+		"Loader with excluded columns", func(t *testing.T) {
+			factory := NewGenReqFactory()
+			factory.options.ExcludeColumns = []string{"authors.name"}
+			req := factory.GenerateRequest()
+
+			resp, err := golang.Generate(ctx, req)
+
+			t.Log("Given the 'select * from authors' SQL query is passed to the generator")
+			t.Log("When the generator is called")
+			t.Log("	Then the generator should return a response without an error")
+			require.NoError(t, err)
+			t.Log("	And the response should contain the generated code")
+			require.NotNil(t, resp)
+			require.Len(t, resp.Files, 2)
+			fn1 := strings.Split(resp.Files[0].Name, "/")[1] + ".snap"
+			fn2 := strings.Split(resp.Files[1].Name, "/")[1] + ".snap"
+			snaps.WithConfig(snaps.Ext("/"+fn1)).
+				MatchStandaloneSnapshot(t, string(resp.Files[0].Contents))
+			snaps.WithConfig(snaps.Ext("/"+fn2)).
+				MatchStandaloneSnapshot(t, string(resp.Files[1].Contents))
+		},
+	)
+
+	t.Run(
+		"Loader with excluded columns of table without schema", func(t *testing.T) {
+			factory := NewGenReqFactory()
+			factory.tableIdent.Schema = ""
+			factory.options.DefaultSchema = ""
+			factory.options.ExcludeColumns = []string{"public.authors.name"}
+			req := factory.GenerateRequest()
+
+			resp, err := golang.Generate(ctx, req)
+
+			t.Log("Given the table without the schema and no default_schema option")
+			t.Log("When the generator is called")
+			t.Log("	Then the generator should return a response without an error")
+			require.NoError(t, err)
+			t.Log("	And the columns should be excluded by the default schema of the catalog")
+			require.NotNil(t, resp)
+			require.Contains(t, string(resp.Files[0].Contents), "SELECT id, status FROM")
+		},
+	)
+
+	t.Run(
+		"Loader with included columns", func(t *testing.T) {
+			factory := NewGenReqFactory()
+			factory.options.IncludeColumns = []string{"public.authors.status"}
+			req := factory.GenerateRequest()
+
+			resp, err := golang.Generate(ctx, req)
+
+			t.Log("Given the 'select * from authors' SQL query is passed to the generator")
+			t.Log("When the generator is called")
+			t.Log("	Then the generator should return a response without an error")
+			require.NoError(t, err)
+			t.Log("	And the response should contain the generated code")
+			require.NotNil(t, resp)
+			require.Len(t, resp.Files, 2)
+			fn1 := strings.Split(resp.Files[0].Name, "/")[1] + ".snap"
+			fn2 := strings.Split(resp.Files[1].Name, "/")[1] + ".snap"
+			snaps.WithConfig(snaps.Ext("/"+fn1)).
+				MatchStandaloneSnapshot(t, string(resp.Files[0].Contents))
+			snaps.WithConfig(snaps.Ext("/"+fn2)).
+				MatchStandaloneSnapshot(t, string(resp.Files[1].Contents))
+		},
+	)
+
+	t.Run(
+		"Loader for materialized view", func(t *testing.T) {
+			factory := NewGenReqFactory()
+			factory.AddTable("author_stats_mv", getViewColumns)
+			factory.options.Views = []opts.View{
+				{
+					Name: "author_stats_mv",
+					Key:  "author_id",
+				},
+			}
+			req := factory.GenerateRequest()
+
+			resp, err := golang.Generate(ctx, req)
+
+			t.Log("Given the materialized view with the configured key is in the catalog")
+			t.Log("When the generator is called")
+			t.Log("	Then the generator should return a response without an error")
+			require.NoError(t, err)
+			t.Log("	And the response should contain the loader for the view")
+			require.NotNil(t, resp)
+			require.Len(t, resp.Files, 3)
+			fn := strings.Split(resp.Files[1].Name, "/")[1] + ".snap"
+			snaps.WithConfig(snaps.Ext("/"+fn)).
+				MatchStandaloneSnapshot(t, string(resp.Files[1].Contents))
+		},
+	)
+
+	t.Run(
+		"Skip views", func(t *testing.T) {
+			factory := NewGenReqFactory()
+			factory.AddTable("author_stats_mv", getViewColumns)
+			factory.options.Views = []opts.View{
+				{
+					Name: "public.author_stats_mv",
+					Key:  "author_id",
+				},
+			}
+			factory.options.ExcludeViews = true
+			req := factory.GenerateRequest()
+
+			resp, err := golang.Generate(ctx, req)
+
+			t.Log("Given the materialized view is in the catalog and views are excluded")
+			t.Log("When the generator is called")
+			t.Log("	Then the generator should return a response without an error")
+			require.NoError(t, err)
+			t.Log("	And the response should not contain the loader for the view")
+			require.NotNil(t, resp)
+			require.Len(t, resp.Files, 2)
+			for _, file := range resp.Files {
+				require.NotContains(t, file.Name, "author_stat")
+			}
+		},
+	)
+
+	t.Run(
+		"Include tables by pattern", func(t *testing.T) {
+			factory := NewGenReqFactory()
+			factory.AddTable("author_stats_mv", getViewColumns)
+			factory.AddTable("audit_log", getViewColumns)
+			factory.options.PrimaryKeysColumns = []string{"a*_*.author_id"}
+			factory.options.IncludeTables = []string{"public.a*"}
+			factory.options.ExcludeTables = []string{"public.audit_*"}
+			req := factory.GenerateRequest()
+
+			resp, err := golang.Generate(ctx, req)
+
+			t.Log("Given the tables are selected by glob patterns")
+			t.Log("When the generator is called")
+			t.Log("	Then the generator should return a response without an error")
+			require.NoError(t, err)
+			t.Log("	And the response should contain the loaders for the matched tables only")
+			require.NotNil(t, resp)
+			require.Len(t, resp.Files, 3)
+			require.Equal(t, "dataloader/author.go", resp.Files[0].Name)
+			require.Equal(t, "dataloader/author_stats_mv.go", resp.Files[1].Name)
+		},
+	)
+
+	t.Run(
+		"Loader with schema-qualified id", func(t *testing.T) {
+			factory := NewGenReqFactory()
+			factory.AddSchemaTable("archive", "authors", getDefaultColumns)
+			factory.options.PrimaryKeysColumns = []string{"archive.authors.name"}
+			req := factory.GenerateRequest()
+
+			resp, err := golang.Generate(ctx, req)
+
+			t.Log("Given two schemas contain the authors table")
+			t.Log("	And the primary key column is configured for one of them")
+			t.Log("When the generator is called")
+			t.Log("	Then the generator should return a response without an error")
+			require.NoError(t, err)
+			t.Log("	And only the loader of the configured schema should use the changed key")
+			require.NotNil(t, resp)
+			require.Len(t, resp.Files, 3)
+			require.Equal(t, "dataloader/archive_author.go", resp.Files[0].Name)
+			require.Contains(t, string(resp.Files[0].Contents), "WHERE name = ANY($1)")
+			require.Equal(t, "dataloader/author.go", resp.Files[1].Name)
+			require.Contains(t, string(resp.Files[1].Contents), "WHERE id = ANY($1)")
+		},
+	)
+
+	t.Run(
+		"Loader With LRU Cache and ttl in days", func(t *testing.T) {
+			factory := NewGenReqFactory()
+			factory.options.Cache = []opts.Cache{
+				{
+					Table: "public.authors",
+					Type:  "lru",
+					Ttl:   "1d3h20m40s",
+					Size:  10,
+				},
+			}
+			req := factory.GenerateRequest()
+
+			resp, err := golang.Generate(ctx, req)
+
+			t.Log("Given the cache ttl contains days")
+			t.Log("When the generator is called")
+			t.Log("	Then the generator should return a response without an error")
+			require.NoError(t, err)
+			t.Log("	And the ttl should be precomputed in the generated code")
+			require.NotNil(t, resp)
+			require.Len(t, resp.Files, 2)
+			require.Contains(
+				t,
+				string(resp.Files[0].Contents),
+				"const authorLoaderCacheTtl time.Duration = 27*time.Hour + 20*time.Minute + 40*time.Second",
+			)
+		},
+	)
+
+	t.Run(
+		"Loader With invalid cache ttl", func(t *testing.T) {
+			factory := NewGenReqFactory()
+			factory.options.Cache = []opts.Cache{
+				{
+					Table: "public.authors",
+					Type:  "lru",
+					Ttl:   "1 day",
+				},
+			}
+			req := factory.GenerateRequest()
+
+			_, err := golang.Generate(ctx, req)
+
+			t.Log("Given the cache ttl is invalid")
+			t.Log("When the generator is called")
+			t.Log("	Then the generator should return an error")
+			require.ErrorContains(t, err, "cache[0].ttl")
+		},
+	)
+
+	t.Run(
+		"Loader factory from user template", func(t *testing.T) {
+			dir := t.TempDir()
+			tpl := `{{define "loader_factory.tmpl"}}package {{ .Package }}
+
+// Tables: {{ range .Structs }}{{ .FullTableName }} {{ end }}
+{{end}}`
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "loader_factory.tmpl"), []byte(tpl), 0o644))
+			factory := NewGenReqFactory()
+			factory.options.TemplatesDir = dir
+			req := factory.GenerateRequest()
+
+			resp, err := golang.Generate(ctx, req)
+
+			t.Log("Given the loader_factory.tmpl template is in the templates directory")
+			t.Log("When the generator is called")
+			t.Log("	Then the generator should return a response without an error")
+			require.NoError(t, err)
+			t.Log("	And the factory should be rendered by the user template")
+			require.NotNil(t, resp)
+			require.Len(t, resp.Files, 2)
+			require.Equal(t, "package dataloader\n\n// Tables: public.authors\n", string(resp.Files[1].Contents))
+			t.Log("	And the loader should be rendered by the built-in template")
+			require.Contains(t, string(resp.Files[0].Contents), "type AuthorLoader struct")
+		},
+	)
+
+	t.Run(
+		"Loader from user template file", func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "my_loader.tmpl")
+			tpl := `package {{ .Package }}
+
+// {{ .Struct.LoaderName }} loads {{ toSnake .Struct.Type.TypeName }} by {{ .PrimaryKeyColumnName }}.
+`
+			require.NoError(t, os.WriteFile(file, []byte(tpl), 0o644))
+			factory := NewGenReqFactory()
+			factory.options.DataLoaderTemplate = file
+			req := factory.GenerateRequest()
+
+			resp, err := golang.Generate(ctx, req)
+
+			t.Log("Given the dataloader template file is configured")
+			t.Log("When the generator is called")
+			t.Log("	Then the generator should return a response without an error")
+			require.NoError(t, err)
+			t.Log("	And the loader should be rendered by the user template")
+			require.NotNil(t, resp)
+			require.Equal(t, "package dataloader\n\n// AuthorLoader loads author by id.\n", string(resp.Files[0].Contents))
+		},
+	)
+
+	t.Run(
+		"Verify output of user template", func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "my_loader.tmpl")
+			tpl := `package {{ .Package }}
+
+func Find{{ .Struct.Type.TypeName }}() {{ .Struct.Type.TypeWithPackage }} {
+	return {{ .Struct.Type.TypeWithPackage }}{}
+}
+`
+			require.NoError(t, os.WriteFile(file, []byte(tpl), 0o644))
+			factory := NewGenReqFactory()
+			factory.options.DataLoaderTemplate = file
+			factory.options.VerifyOutput = true
+			req := factory.GenerateRequest()
+
+			_, err := golang.Generate(ctx, req)
+
+			t.Log("Given the dataloader template renders code without the imports")
+			t.Log("When the generator is called with the verify_output option")
+			t.Log("	Then the generator should return an error with the file, line and table")
+			require.ErrorContains(t, err, "dataloader/author.go:3:19: undefined: model (table public.authors)")
+		},
+	)
+
+	t.Run(
+		"Single file layout", func(t *testing.T) {
+			factory := NewGenReqFactory()
+			factory.AddTable("books", getDefaultColumns)
+			factory.options.OutputLayout = opts.OutputLayoutSingleFile
+			req := factory.GenerateRequest()
+
+			resp, err := golang.Generate(ctx, req)
+
+			t.Log("Given the single_file output layout")
+			t.Log("When the generator is called")
+			t.Log("	Then the generator should return a response without an error")
+			require.NoError(t, err)
+			t.Log("	And all loaders and the factory should be in one file")
+			require.NotNil(t, resp)
+			require.Len(t, resp.Files, 1)
+			require.Equal(t, "dataloader/loaders.go", resp.Files[0].Name)
+			snaps.WithConfig(snaps.Ext("/loaders.go.snap")).
+				MatchStandaloneSnapshot(t, string(resp.Files[0].Contents))
+		},
+	)
+
+	t.Run(
+		"Per schema layout", func(t *testing.T) {
+			factory := NewGenReqFactory()
+			factory.AddTable("books", getDefaultColumns)
+			factory.AddSchemaTable("archive", "authors", getDefaultColumns)
+			factory.options.OutputLayout = opts.OutputLayoutPerSchema
+			req := factory.GenerateRequest()
+
+			resp, err := golang.Generate(ctx, req)
+
+			t.Log("Given the per_schema output layout")
+			t.Log("	And the tables in two schemas")
+			t.Log("When the generator is called")
+			t.Log("	Then the generator should return a response without an error")
+			require.NoError(t, err)
+			t.Log("	And the loaders of each schema should be in one file")
+			require.NotNil(t, resp)
+			require.Len(t, resp.Files, 3)
+			require.Equal(t, "dataloader/archive_loaders.go", resp.Files[0].Name)
+			require.Contains(t, string(resp.Files[0].Contents), "type ArchiveAuthorLoader struct")
+			require.Equal(t, "dataloader/public_loaders.go", resp.Files[1].Name)
+			require.Contains(t, string(resp.Files[1].Contents), "type AuthorLoader struct")
+			require.Contains(t, string(resp.Files[1].Contents), "type BookLoader struct")
+			require.Equal(t, "dataloader/loader_factory.go", resp.Files[2].Name)
+		},
+	)
+
+	t.Run(
+		"Loader filename pattern", func(t *testing.T) {
+			factory := NewGenReqFactory()
+			factory.options.LoaderFilenamePattern = "{schema}_{table}.go"
+			req := factory.GenerateRequest()
+
+			resp, err := golang.Generate(ctx, req)
+
+			t.Log("Given the loader_filename pattern")
+			t.Log("When the generator is called")
+			t.Log("	Then the generator should return a response without an error")
+			require.NoError(t, err)
+			t.Log("	And the loader file should be named by the pattern")
+			require.NotNil(t, resp)
+			require.Len(t, resp.Files, 2)
+			require.Equal(t, "dataloader/public_authors.go", resp.Files[0].Name)
+		},
+	)
+
+	t.Run(
+		"Loader filename pattern of the count loader", func(t *testing.T) {
+			factory := NewGenReqFactory().
+				AddTable("books", getBookColumns)
+			factory.options.CountLoaders = []opts.CountLoader{{Column: "books.author_id"}}
+			factory.options.LoaderFilenamePattern = "{table}_{name}.go"
+			req := factory.GenerateRequest()
+
+			resp, err := golang.Generate(ctx, req)
+
+			t.Log("Given the loader_filename pattern with the table name and the count loader")
+			t.Log("When the generator is called")
+			t.Log("	Then the generator should return a response without an error")
+			require.NoError(t, err)
+			t.Log("	And the count loader file should be named by the counted table")
+			require.NotNil(t, resp)
+			names := make([]string, 0, len(resp.Files))
+			for _, f := range resp.Files {
+				names = append(names, f.Name)
+			}
+			require.Contains(t, names, "dataloader/books_books_count_by_author_id.go")
+		},
+	)
+
+	t.Run(
+		"Loader filename in the package of the models", func(t *testing.T) {
+			factory := NewGenReqFactory()
+			factory.options.ModelImport = "internal/dataloader"
+			req := factory.GenerateRequest()
+
+			resp, err := golang.Generate(ctx, req)
+
+			t.Log("Given the loaders generated to the package of the models without the loader_filename pattern")
+			t.Log("When the generator is called")
+			t.Log("	Then the generator should return a response without an error")
+			require.NoError(t, err)
+			t.Log("	And the loader file should have the _loader suffix")
+			require.NotNil(t, resp)
+			require.Len(t, resp.Files, 2)
+			require.Equal(t, "author_loader.go", resp.Files[0].Name)
+		},
+	)
+	t.Run(
+		"Verify output of single file", func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "my_loader.tmpl")
+			tpl := `package {{ .Package }}
+
+func Find{{ .Struct.Type.TypeName }}() {{ .Struct.Type.TypeWithPackage }} {
+	return {{ .Struct.Type.TypeWithPackage }}{Missing: true}
+}
+`
+			require.NoError(t, os.WriteFile(file, []byte(tpl), 0o644))
+			factory := NewGenReqFactory()
+			factory.AddTable("books", getDefaultColumns)
+			factory.options.DataLoaderTemplate = file
+			factory.options.OutputLayout = opts.OutputLayoutSingleFile
+			factory.options.VerifyOutput = true
+			req := factory.GenerateRequest()
+
+			_, err := golang.Generate(ctx, req)
+
+			t.Log("Given the dataloader template renders code that does not compile")
+			t.Log("	And the single_file output layout")
+			t.Log("When the generator is called with the verify_output option")
+			t.Log("	Then the generator should return an error with the table of each line")
+			require.ErrorContains(t, err, "dataloader/loaders.go:10:22: unknown field Missing in struct literal of type model.Author (table public.authors)")
+			require.ErrorContains(t, err, "dataloader/loaders.go:14:20: unknown field Missing in struct literal of type model.Book (table public.books)")
+		},
+	)
+
+	for _, sqlPackage := range []string{"pgx/v5", "pgx/v4"} {
+		t.Run(
+			"Verify output of built-in templates with "+sqlPackage, func(t *testing.T) {
+				factory := NewGenReqFactory().
+					AddTable("books", getBookColumns).
+					AddTable("posts", keyColumns("bigint")).
+					AddTable("tags", keyColumns("bigint")).
+					AddTable("post_tags", getPostTagColumns).
+					AddTable("files", keyColumns("bytea")).
+					AddTable("events", keyColumns("timestamptz")).
+					AddTable("prices", keyColumns("pg_catalog.numeric"))
+				factory.options.SqlPackage = sqlPackage
+				factory.options.VerifyOutput = true
+				factory.options.EmitFakes = true
+				factory.options.EmitExists = true
+				factory.options.Gqlgen = true
+				factory.options.PrimaryKeysColumns = []string{"posts.id", "tags.id"}
+				factory.options.ForeignKeys = []opts.ForeignKey{{Column: "books.author_id", References: "authors"}}
+				factory.options.ManyToMany = []opts.ManyToMany{
+					{Relation: "posts.id -> post_tags(post_id, tag_id) -> tags.id", JoinColumns: []string{"position"}},
+				}
+				factory.options.CountLoaders = []opts.CountLoader{{Column: "books.author_id"}}
+				factory.options.Retry = &opts.Retry{MaxAttempts: 2}
+				factory.options.Tables = []opts.TableOptions{{Table: "authors", QueryTimeout: "1s"}}
+				req := factory.GenerateRequest()
+
+				_, err := golang.Generate(ctx, req)
+
+				t.Log("Given the loaders of all built-in templates")
+				t.Log("When the generator is called with the verify_output option")
+				t.Log("	Then the generated code should be type-checked without an error")
+				require.NoError(t, err)
+			},
+		)
+	}
+
+	t.Run(
+		"Manifest", func(t *testing.T) {
+			factory := NewGenReqFactory()
+			factory.AddTable("book_stats", getViewColumns)
+			factory.AddTable("books", getDefaultColumns)
+			factory.options.ExcludeTables = []string{"books"}
+			factory.options.Cache = []opts.Cache{
+				{
+					Table: "public.authors",
+					Type:  "lru",
+					Ttl:   "1d12h",
+					Size:  100,
+				},
+			}
+			factory.options.EmitManifest = true
+			req := factory.GenerateRequest()
+
+			resp, err := golang.Generate(ctx, req)
+
+			t.Log("Given the emit_manifest option")
+			t.Log("	And the tables skipped for different reasons")
+			t.Log("When the generator is called")
+			t.Log("	Then the generator should return a response without an error")
+			require.NoError(t, err)
+			t.Log("	And the manifest should describe the loaders and the skipped tables")
+			require.NotNil(t, resp)
+			require.Len(t, resp.Files, 3)
+			require.Equal(t, "dataloader/dataloader_manifest.json", resp.Files[2].Name)
+			snaps.WithConfig(snaps.Ext("/dataloader_manifest.json.snap")).
+				MatchStandaloneSnapshot(t, string(resp.Files[2].Contents))
+		},
+	)
+
+	t.Run(
+		"Gqlgen resolver helpers", func(t *testing.T) {
+			factory := NewGenReqFactory()
+			factory.AddTable("books", getBookColumns)
+			factory.options.Gqlgen = true
+			factory.options.ForeignKeys = []opts.ForeignKey{
+				{Column: "books.author_id", References: "authors"},
+				{Column: "books.editor_id", References: "authors"},
+			}
+			req := factory.GenerateRequest()
+
+			resp, err := golang.Generate(ctx, req)
+
+			t.Log("Given the gqlgen option")
+			t.Log("	And the not null and the nullable foreign keys")
+			t.Log("When the generator is called")
+			t.Log("	Then the generator should return a response without an error")
+			require.NoError(t, err)
+			t.Log("	And the middleware and the resolver helpers should be generated")
 			require.NotNil(t, resp)
-			require.Len(t, resp.Files, 2)
-			fn1 := strings.Split(resp.Files[0].Name, "/")[1] + ".snap"
-			fn2 := strings.Split(resp.Files[1].Name, "/")[1] + ".snap"
-			snaps.WithConfig(snaps.Ext("/"+fn1)).
-				MatchStandaloneSnapshot(t, string(resp.Files[0].Contents))
-			snaps.WithConfig(snaps.Ext("/"+fn2)).
-				MatchStandaloneSnapshot(t, string(resp.Files[1].Contents))
+			require.Len(t, resp.Files, 4)
+			require.Equal(t, "dataloader/gqlgen.go", resp.Files[3].Name)
+			snaps.WithConfig(snaps.Ext("/gqlgen.go.snap")).
+				MatchStandaloneSnapshot(t, string(resp.Files[3].Contents))
 		},
 	)
 
 	t.Run(
-		"Loader with changed id", func(t *testing.T) {
+		"Gqlgen foreign key of other type", func(t *testing.T) {
 			factory := NewGenReqFactory()
-			factory.options.PrimaryKeysColumns = []string{"authors.name"}
+			factory.AddTable("books", getBookColumns)
+			factory.options.Gqlgen = true
+			factory.options.ForeignKeys = []opts.ForeignKey{
+				{Column: "books.title", References: "authors", Name: "Author"},
+			}
+			req := factory.GenerateRequest()
+
+			_, err := golang.Generate(ctx, req)
+
+			t.Log("Given the foreign key of the type different from the referenced key")
+			t.Log("When the generator is called")
+			t.Log("	Then the generator should return an error")
+			require.ErrorContains(t, err, "foreign_keys[0]: the type string of books.title does not match the key type pgtype.UUID of authors")
+		},
+	)
+
+	t.Run(
+		"Loader scanning by column names", func(t *testing.T) {
+			factory := NewGenReqFactory()
+			factory.options.ScanMode = "by_name"
 			req := factory.GenerateRequest()
 
 			resp, err := golang.Generate(ctx, req)
 
-			t.Log("Given the 'select * from authors' SQL query is passed to the generator")
+			t.Log("Given the by_name scan mode")
 			t.Log("When the generator is called")
 			t.Log("	Then the generator should return a response without an error")
 			require.NoError(t, err)
-			t.Log("	And the response should contain the generated code")
+			t.Log("	And the loader should select the known columns and scan them by the column names")
 			require.NotNil(t, resp)
 			require.Len(t, resp.Files, 2)
-			fn1 := strings.Split(resp.Files[0].Name, "/")[1] + ".snap"
-			fn2 := strings.Split(resp.Files[1].Name, "/")[1] + ".snap"
-			snaps.WithConfig(snaps.Ext("/"+fn1)).
+			snaps.WithConfig(snaps.Ext("/author.go.snap")).
 				MatchStandaloneSnapshot(t, string(resp.Files[0].Contents))
-			snaps.WithConfig(snaps.Ext("/"+fn2)).
-				MatchStandaloneSnapshot(t, string(resp.Files[1].Contents))
 		},
 	)
 
 	t.Run(
-		"Skip loader", func(t *testing.T) {
+		"Loader scanning all columns by column names", func(t *testing.T) {
 			factory := NewGenReqFactory()
-			factory.options.ExcludeTables = []string{"public.authors"}
+			factory.options.ScanMode = "by_name"
+			factory.options.SelectAllColumns = true
 			req := factory.GenerateRequest()
 
 			resp, err := golang.Generate(ctx, req)
 
-			t.Log("Given the 'select * from authors' SQL query is passed to the generator")
+			t.Log("Given the by_name scan mode and the select_all_columns option")
 			t.Log("When the generator is called")
 			t.Log("	Then the generator should return a response without an error")
 			require.NoError(t, err)
-			t.Log("	And the response should contain the generated code")
+			t.Log("	And the loader should select all columns")
 			require.NotNil(t, resp)
-			require.Len(t, resp.Files, 0)
+			require.Len(t, resp.Files, 2)
+			require.Contains(t, string(resp.Files[0].Contents), "const AuthorLoaderQuery = `SELECT * FROM \"public\".\"authors\" WHERE id = ANY($1)`")
 		},
 	)
 
 	t.Run(
-		"Loader with id of enum type", func(t *testing.T) {
+		"Loader with query limits", func(t *testing.T) {
 			factory := NewGenReqFactory()
-			factory.options.PrimaryKeysColumns = []string{"authors.status"}
+			factory.AddTable("books", getDefaultColumns)
+			factory.options.MaxKeysPerQuery = 500
+			factory.options.Retry = &opts.Retry{MaxAttempts: 2}
+			factory.options.Tables = []opts.TableOptions{
+				{
+					Table:            "authors",
+					MaxKeysPerQuery:  100,
+					QueryConcurrency: 2,
+					QueryTimeout:     "1m30s",
+					DuplicateKeys:    "error",
+					Retry: &opts.Retry{
+						MaxAttempts: 4,
+						BaseDelay:   "20ms",
+						MaxDelay:    "1s",
+					},
+				},
+			}
 			req := factory.GenerateRequest()
 
 			resp, err := golang.Generate(ctx, req)
 
-			t.Log("Given the 'select * from authors' SQL query is passed to the generator")
+			t.Log("Given the global max_keys_per_query and retry options")
+			t.Log("	And the tables option overriding them for the authors table with the query timeout")
 			t.Log("When the generator is called")
 			t.Log("	Then the generator should return a response without an error")
 			require.NoError(t, err)
-			t.Log("	And the response should contain the generated code")
+			t.Log("	And the loaders should split the batches by the configured limits")
 			require.NotNil(t, resp)
-			require.Len(t, resp.Files, 2)
-			fn1 := strings.Split(resp.Files[0].Name, "/")[1] + ".snap"
-			fn2 := strings.Split(resp.Files[1].Name, "/")[1] + ".snap"
-			snaps.WithConfig(snaps.Ext("/"+fn1)).
+			require.Len(t, resp.Files, 3)
+			require.Equal(t, "dataloader/author.go", resp.Files[0].Name)
+			require.Equal(t, "dataloader/book.go", resp.Files[1].Name)
+			snaps.WithConfig(snaps.Ext("/author.go.snap")).
 				MatchStandaloneSnapshot(t, string(resp.Files[0].Contents))
-			snaps.WithConfig(snaps.Ext("/"+fn2)).
-				MatchStandaloneSnapshot(t, string(resp.Files[1].Contents))
+			require.Contains(t, string(resp.Files[1].Contents), "MaxKeysPerQuery: 500,")
+			require.Contains(t, string(resp.Files[1].Contents), "MaxAttempts: 2,")
+			require.NotContains(t, string(resp.Files[1].Contents), "QueryConcurrency")
+		},
+	)
+
+	t.Run(
+		"Loaders with key adapters", func(t *testing.T) {
+			factory := NewGenReqFactory().
+				AddTable("files", keyColumns("bytea")).
+				AddTable("tags", keyColumns("citext")).
+				AddTable("events", keyColumns("timestamptz")).
+				AddTable("prices", keyColumns("pg_catalog.numeric"))
+			factory.options.EmitFakes = true
+			factory.options.EmitExists = true
+			req := factory.GenerateRequest()
+
+			resp, err := golang.Generate(ctx, req)
+
+			t.Log("Given the tables keyed by the bytea, citext, timestamptz and numeric columns with the existence checks")
+			t.Log("When the generator is called")
+			t.Log("	Then the generator should return a response without an error")
+			require.NoError(t, err)
+			t.Log("	And the loaders should map the keys by the key adapters")
+			require.NotNil(t, resp)
+			require.Len(t, resp.Files, 7)
+			require.Equal(t, "dataloader/file.go", resp.Files[2].Name)
+			snaps.WithConfig(snaps.Ext("/file.go.snap")).
+				MatchStandaloneSnapshot(t, string(resp.Files[2].Contents))
+			require.Contains(t, string(resp.Files[4].Contents), "var TagLoaderKeyAdapter = dl.NormalizedKeyAdapter(dl.LowerKey)")
+			require.Contains(t, string(resp.Files[1].Contents), "key.Time = dl.NormalizeTime(key.Time)")
+			require.Equal(t, "dataloader/price.go", resp.Files[3].Name)
+			snaps.WithConfig(snaps.Ext("/price.go.snap")).
+				MatchStandaloneSnapshot(t, string(resp.Files[3].Contents))
+			require.NotContains(t, string(resp.Files[0].Contents), "AuthorLoaderKeyAdapter")
+			require.Equal(t, "dataloader/dataloadertest/fakes.go", resp.Files[6].Name)
+			snaps.WithConfig(snaps.Ext("/fakes.go.snap")).
+				MatchStandaloneSnapshot(t, string(resp.Files[6].Contents))
+		},
+	)
+
+	t.Run(
+		"Loaders with key adapters of database/sql", func(t *testing.T) {
+			factory := NewGenReqFactory().
+				AddTable("tags", keyColumns("citext")).
+				AddTable("events", keyColumns("timestamptz")).
+				AddTable("prices", keyColumns("numeric"))
+			factory.options.SqlPackage = "database/sql"
+			factory.options.KeyNormalizers = map[string]string{"citext": "github.com/acme/keys.Fold"}
+			req := factory.GenerateRequest()
+
+			resp, err := golang.Generate(ctx, req)
+
+			t.Log("Given the database/sql package and the custom normaliser of the citext keys")
+			t.Log("When the generator is called")
+			t.Log("	Then the generator should return a response without an error")
+			require.NoError(t, err)
+			t.Log("	And the keys should be normalised by the custom and the built-in functions")
+			require.NotNil(t, resp)
+			require.Len(t, resp.Files, 5)
+			require.Contains(t, string(resp.Files[3].Contents), "var TagLoaderKeyAdapter = dl.NormalizedKeyAdapter(keys.Fold)")
+			require.Contains(t, string(resp.Files[3].Contents), `"github.com/acme/keys"`)
+			require.Contains(t, string(resp.Files[1].Contents), "var EventLoaderKeyAdapter = dl.NormalizedKeyAdapter(dl.NormalizeTime)")
+			require.Contains(t, string(resp.Files[2].Contents), "var PriceLoaderKeyAdapter = dl.NormalizedKeyAdapter(dl.NormalizeDecimal)")
+		},
+	)
+
+	t.Run(
+		"Key normaliser from the package with the name differing from the path", func(t *testing.T) {
+			factory := NewGenReqFactory().
+				AddTable("tags", keyColumns("citext")).
+				AddTable("slugs", keyColumns("varchar"))
+			factory.options.KeyNormalizers = map[string]string{
+				"citext":  "github.com/acme/go-keys.Fold",
+				"varchar": "example.com/slugs/v2.Normalize",
+			}
+			req := factory.GenerateRequest()
+
+			resp, err := golang.Generate(ctx, req)
+
+			t.Log("Given the normalisers from the go-prefixed and the versioned packages")
+			t.Log("When the generator is called")
+			t.Log("	Then the generator should return a response without an error")
+			require.NoError(t, err)
+			t.Log("	And the normalisers should be qualified by the package names imported with the aliases")
+			require.NotNil(t, resp)
+			var tags, slugs string
+			for _, f := range resp.Files {
+				switch f.Name {
+				case "dataloader/tag.go":
+					tags = string(f.Contents)
+				case "dataloader/slug.go":
+					slugs = string(f.Contents)
+				}
+			}
+			require.Contains(t, tags, "var TagLoaderKeyAdapter = dl.NormalizedKeyAdapter(keys.Fold)")
+			require.Contains(t, tags, `keys "github.com/acme/go-keys"`)
+			require.Contains(t, slugs, "var SlugLoaderKeyAdapter = dl.NormalizedKeyAdapter(slugs.Normalize)")
+			require.Contains(t, slugs, `"example.com/slugs/v2"`)
+			require.NotContains(t, slugs, "v2.Normalize")
+		},
+	)
+	t.Run(
+		"Query loader with embedded tables", func(t *testing.T) {
+			factory := NewGenReqFactory().
+				AddTable("books", getBookColumns).
+				AddQuery(
+					&plugin.Query{
+						Text: "SELECT books.id, books.author_id, books.editor_id, books.title, " +
+							"authors.id, authors.name, authors.status, authors.id = books.editor_id AS self_edited " +
+							"FROM books JOIN authors ON authors.id = books.author_id WHERE books.id = ANY($1::uuid[])",
+						Name: "ListBooksWithAuthors",
+						Cmd:  ":many",
+						Columns: []*plugin.Column{
+							{Name: "books", EmbedTable: &plugin.Identifier{Name: "books"}},
+							{Name: "authors", EmbedTable: &plugin.Identifier{Name: "authors"}},
+							{Name: "self_edited", NotNull: true, Type: &plugin.Identifier{Name: "bool"}},
+						},
+						Params: []*plugin.Parameter{
+							{Number: 1, Column: &plugin.Column{Name: "ids", IsArray: true, Type: &plugin.Identifier{Name: "uuid"}}},
+						},
+						Filename: "books.sql",
+					},
+				)
+			factory.options.QueryLoaders = []opts.QueryLoader{{Query: "ListBooksWithAuthors", Key: "books.id"}}
+			factory.options.EmitFakes = true
+			req := factory.GenerateRequest()
+
+			resp, err := golang.Generate(ctx, req)
+
+			t.Log("Given the query loader over the query embedding the books and authors tables")
+			t.Log("When the generator is called")
+			t.Log("	Then the generator should return a response without an error")
+			require.NoError(t, err)
+			t.Log("	And the loader should scan the rows into the fields of the embedded structs")
+			require.NotNil(t, resp)
+			require.Len(t, resp.Files, 5)
+			require.Equal(t, "dataloader/list_books_with_authors.go", resp.Files[2].Name)
+			snaps.WithConfig(snaps.Ext("/list_books_with_authors.go.snap")).
+				MatchStandaloneSnapshot(t, string(resp.Files[2].Contents))
+			require.Contains(t, string(resp.Files[3].Contents), "func (f *LoaderFactory) ListBooksWithAuthorsLoader() ListBooksWithAuthorsLoaderI {")
+			require.Contains(t, string(resp.Files[4].Contents), "l.items[item.Book.ID] = item")
+		},
+	)
+
+	t.Run(
+		"Many-to-many loader", func(t *testing.T) {
+			factory := NewGenReqFactory().
+				AddTable("posts", keyColumns("bigint")).
+				AddTable("tags", keyColumns("bigint")).
+				AddTable("post_tags", getPostTagColumns)
+			factory.options.PrimaryKeysColumns = []string{"posts.id", "tags.id"}
+			factory.options.ManyToMany = []opts.ManyToMany{
+				{Relation: "posts.id -> post_tags(post_id, tag_id) -> tags.id"},
+			}
+			factory.options.EmitFakes = true
+			req := factory.GenerateRequest()
+
+			resp, err := golang.Generate(ctx, req)
+
+			t.Log("Given the many-to-many relation of the posts and tags through post_tags")
+			t.Log("When the generator is called")
+			t.Log("	Then the generator should return a response without an error")
+			require.NoError(t, err)
+			t.Log("	And the response should contain the loader of the tags by the post keys")
+			require.NotNil(t, resp)
+			require.Len(t, resp.Files, 6)
+			require.Equal(t, "dataloader/tags_by_post_id.go", resp.Files[3].Name)
+			snaps.WithConfig(snaps.Ext("/tags_by_post_id.go.snap")).
+				MatchStandaloneSnapshot(t, string(resp.Files[3].Contents))
+			require.Contains(t, string(resp.Files[4].Contents), "func (f *LoaderFactory) TagsByPostIDLoader() TagsByPostIDLoaderI {")
+			require.Equal(t, "dataloader/dataloadertest/fakes.go", resp.Files[5].Name)
+			require.Contains(t, string(resp.Files[5].Contents), "TagsByPostID *FakeTagsByPostIDLoader")
+		},
+	)
+
+	t.Run(
+		"Many-to-many loader with join columns", func(t *testing.T) {
+			factory := NewGenReqFactory().
+				AddTable("posts", keyColumns("bigint")).
+				AddTable("tags", keyColumns("bigint")).
+				AddTable("post_tags", getPostTagColumns)
+			factory.options.PrimaryKeysColumns = []string{"posts.id", "tags.id"}
+			factory.options.ManyToMany = []opts.ManyToMany{
+				{
+					Relation:    "posts.id -> post_tags(post_id, tag_id) -> tags.id",
+					Name:        "PostTags",
+					JoinColumns: []string{"position"},
+				},
+			}
+			factory.options.EmitFakes = true
+			req := factory.GenerateRequest()
+
+			resp, err := golang.Generate(ctx, req)
+
+			t.Log("Given the many-to-many relation with the position column of the join table")
+			t.Log("When the generator is called")
+			t.Log("	Then the generator should return a response without an error")
+			require.NoError(t, err)
+			t.Log("	And the loader should return the tags with the positions")
+			require.NotNil(t, resp)
+			require.Len(t, resp.Files, 6)
+			require.Equal(t, "dataloader/post_tags.go", resp.Files[3].Name)
+			snaps.WithConfig(snaps.Ext("/post_tags.go.snap")).
+				MatchStandaloneSnapshot(t, string(resp.Files[3].Contents))
+			require.Contains(t, string(resp.Files[5].Contents), "groups map[int64][]dataloader.PostTagsItem")
+		},
+	)
+
+	t.Run(
+		"Count loaders", func(t *testing.T) {
+			factory := NewGenReqFactory().
+				AddTable("books", getBookColumns)
+			factory.options.CountLoaders = []opts.CountLoader{
+				{Column: "books.author_id"},
+				{Column: "books.editor_id", Name: "TitledBooksCountByEditorID", Filter: "title <> ''"},
+			}
+			factory.options.EmitFakes = true
+			req := factory.GenerateRequest()
+
+			resp, err := golang.Generate(ctx, req)
+
+			t.Log("Given the count loaders of the books by the authors and the editors")
+			t.Log("When the generator is called")
+			t.Log("	Then the generator should return a response without an error")
+			require.NoError(t, err)
+			t.Log("	And the response should contain the count loaders")
+			require.NotNil(t, resp)
+			require.Len(t, resp.Files, 6)
+			require.Equal(t, "dataloader/books_count_by_author_id.go", resp.Files[2].Name)
+			snaps.WithConfig(snaps.Ext("/books_count_by_author_id.go.snap")).
+				MatchStandaloneSnapshot(t, string(resp.Files[2].Contents))
+			require.Contains(
+				t,
+				string(resp.Files[3].Contents),
+				"`SELECT editor_id, count(*) FROM \"public\".\"books\" WHERE editor_id = ANY($1) AND (title <> '') GROUP BY editor_id`",
+			)
+			require.Contains(t, string(resp.Files[4].Contents), "func (f *LoaderFactory) BooksCountByAuthorIDLoader() BooksCountByAuthorIDLoaderI {")
+			require.Contains(t, string(resp.Files[5].Contents), "counts map[pgtype.UUID]int64")
+		},
+	)
+
+	t.Run(
+		"Fake loaders", func(t *testing.T) {
+			factory := NewGenReqFactory()
+			factory.options.ModelImport = "github.com/yourorg/yourrepo/models"
+			factory.options.EmitFakes = true
+			factory.options.EmitExists = true
+			req := factory.GenerateRequest()
+
+			resp, err := golang.Generate(ctx, req)
+
+			t.Log("Given the fake loaders generation and the existence checks are enabled")
+			t.Log("When the generator is called")
+			t.Log("	Then the generator should return a response without an error")
+			require.NoError(t, err)
+			t.Log("	And the response should contain the fake loaders")
+			require.NotNil(t, resp)
+			require.Len(t, resp.Files, 3)
+			require.Equal(t, "dataloader/dataloadertest/fakes.go", resp.Files[2].Name)
+			snaps.WithConfig(snaps.Ext("/fakes.go.snap")).
+				MatchStandaloneSnapshot(t, string(resp.Files[2].Contents))
 		},
 	)
 }
@@ -166,6 +1060,7 @@ type genReqFactory struct {
 	columns    []*plugin.Column
 	catalog    *plugin.Catalog
 	query      *plugin.Query
+	queries    []*plugin.Query
 	options    opts.Options
 }
 
@@ -273,6 +1168,158 @@ func getDefaultColumns(tableIdent *plugin.Identifier) []*plugin.Column {
 	}
 }
 
+func getBookColumns(tableIdent *plugin.Identifier) []*plugin.Column {
+	return []*plugin.Column{
+		{
+			Name:    "id",
+			NotNull: true,
+			Table:   tableIdent,
+			Type: &plugin.Identifier{
+				Name: "uuid",
+			},
+		},
+		{
+			Name:    "author_id",
+			NotNull: true,
+			Table:   tableIdent,
+			Type: &plugin.Identifier{
+				Name: "uuid",
+			},
+		},
+		{
+			Name:    "editor_id",
+			NotNull: false,
+			Table:   tableIdent,
+			Type: &plugin.Identifier{
+				Name: "uuid",
+			},
+		},
+		{
+			Name:    "title",
+			NotNull: true,
+			Table:   tableIdent,
+			Type: &plugin.Identifier{
+				Name: "text",
+			},
+		},
+	}
+}
+
+func getPostTagColumns(tableIdent *plugin.Identifier) []*plugin.Column {
+	return []*plugin.Column{
+		{
+			Name:    "post_id",
+			NotNull: true,
+			Table:   tableIdent,
+			Type: &plugin.Identifier{
+				Name: "bigint",
+			},
+		},
+		{
+			Name:    "tag_id",
+			NotNull: true,
+			Table:   tableIdent,
+			Type: &plugin.Identifier{
+				Name: "bigint",
+			},
+		},
+		{
+			Name:    "position",
+			NotNull: true,
+			Table:   tableIdent,
+			Type: &plugin.Identifier{
+				Name: "integer",
+			},
+		},
+	}
+}
+
+// keyColumns returns the columns of a table keyed by the id column of the database type.
+func keyColumns(dbType string) func(tableIdent *plugin.Identifier) []*plugin.Column {
+	return func(tableIdent *plugin.Identifier) []*plugin.Column {
+		return []*plugin.Column{
+			{
+				Name:    "id",
+				NotNull: true,
+				Table:   tableIdent,
+				Type: &plugin.Identifier{
+					Name: dbType,
+				},
+			},
+			{
+				Name:    "name",
+				NotNull: true,
+				Table:   tableIdent,
+				Type: &plugin.Identifier{
+					Name: "text",
+				},
+			},
+		}
+	}
+}
+
+// AddQuery adds a query to the request.
+func (f genReqFactory) AddQuery(query *plugin.Query) genReqFactory {
+	f.queries = append(f.queries, query)
+	return f
+}
+
+// AddTable adds a table to the default schema of the catalog.
+func (f genReqFactory) AddTable(name string, getColumns func(tableIdent *plugin.Identifier) []*plugin.Column) genReqFactory {
+	return f.AddSchemaTable(f.schemaName, name, getColumns)
+}
+
+// AddSchemaTable adds a table to the schema of the catalog. The schema is created if it does not exist.
+func (f genReqFactory) AddSchemaTable(
+	schemaName string,
+	name string,
+	getColumns func(tableIdent *plugin.Identifier) []*plugin.Column,
+) genReqFactory {
+	tableIdent := &plugin.Identifier{
+		Schema: schemaName,
+		Name:   name,
+	}
+	var schema *plugin.Schema
+	for _, s := range f.catalog.Schemas {
+		if s.Name == schemaName {
+			schema = s
+			break
+		}
+	}
+	if schema == nil {
+		schema = &plugin.Schema{Name: schemaName}
+		f.catalog.Schemas = append(f.catalog.Schemas, schema)
+	}
+	schema.Tables = append(
+		schema.Tables, &plugin.Table{
+			Rel:     tableIdent,
+			Columns: getColumns(tableIdent),
+		},
+	)
+	return f
+}
+
+func getViewColumns(tableIdent *plugin.Identifier) []*plugin.Column {
+	return []*plugin.Column{
+		{
+			Name:    "author_id",
+			NotNull: true,
+			Table:   tableIdent,
+			Type: &plugin.Identifier{
+				Name: "uuid",
+			},
+		},
+		{
+			Name:    "books_count",
+			NotNull: true,
+			Table:   tableIdent,
+			Type: &plugin.Identifier{
+				Name: "int8",
+			},
+		},
+	}
+}
+
 func getDefaultCatalog(tableIdent *plugin.Identifier, schemaName string, columns []*plugin.Column) *plugin.Catalog {
 	return &plugin.Catalog{
 		DefaultSchema: schemaName,
@@ -314,7 +1361,7 @@ func (f genReqFactory) GenerateRequest() *plugin.GenerateRequest {
 	}
 
 	req.Catalog = f.catalog
-	req.Queries = []*plugin.Query{f.query}
+	req.Queries = append([]*plugin.Query{f.query}, f.queries...)
 	req.SqlcVersion = "v1.27.0"
 	req.PluginOptions = jsonOpts
 	req.Settings = settings
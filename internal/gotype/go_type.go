@@ -118,6 +118,18 @@ func (g *GoType) PackageName() string {
 	return g.packageName
 }
 
+// HasValidField returns true if the type of the package is the nullable struct with the Valid field,
+// e.g. pgtype.UUID of pgx/v5 or sql.NullString. The types of pgx/v4 have the Status field instead.
+func HasValidField(pkgPath, typeName string) bool {
+	switch pkgPath {
+	case "github.com/jackc/pgx/v5/pgtype":
+		return true
+	case "database/sql":
+		return strings.HasPrefix(typeName, "Null")
+	}
+	return false
+}
+
 type DbTOGoTypeTransformer interface {
 	ToGoType(col *plugin.Column) GoType
 }
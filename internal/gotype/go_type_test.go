@@ -81,3 +81,22 @@ func TestNewGoType(t *testing.T) {
 		require.True(t, gt.IsArray())
 	})
 }
+
+func TestHasValidField(t *testing.T) {
+	t.Run("pgx v5 types have the Valid field", func(t *testing.T) {
+		require.True(t, HasValidField("github.com/jackc/pgx/v5/pgtype", "UUID"))
+	})
+
+	t.Run("sql null types have the Valid field", func(t *testing.T) {
+		require.True(t, HasValidField("database/sql", "NullString"))
+		require.False(t, HasValidField("database/sql", "RawBytes"))
+	})
+
+	t.Run("pgx v4 types have the Status field", func(t *testing.T) {
+		require.False(t, HasValidField("github.com/jackc/pgtype", "UUID"))
+	})
+
+	t.Run("types of other packages named pgtype", func(t *testing.T) {
+		require.False(t, HasValidField("github.com/acme/pgtype", "UUID"))
+	})
+}
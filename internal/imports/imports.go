@@ -3,9 +3,13 @@ package imports
 import (
 	"fmt"
 	"github.com/debugger84/sqlc-dataloader/internal/opts"
+	"regexp"
 	"sort"
+	"strings"
 )
 
+var versionSuffix = regexp.MustCompile(`^v[0-9]+$`)
+
 type Container interface {
 	GetImports() []Import
 }
@@ -22,6 +26,19 @@ func (i Import) Format() string {
 	return fmt.Sprintf(`%s "%s"`, i.Alias, i.Path)
 }
 
+// PackageName returns the conventional name of the package by its import path,
+// e.g. "decimal" for "github.com/acme/go-decimal" and "keys" for "example.com/keys/v2".
+func PackageName(pkgPath string) string {
+	parts := strings.Split(pkgPath, "/")
+	name := parts[len(parts)-1]
+	if versionSuffix.MatchString(name) && len(parts) > 1 {
+		name = parts[len(parts)-2]
+	}
+	name = strings.TrimPrefix(name, "go-")
+	name = strings.TrimSuffix(name, "-go")
+	return strings.NewReplacer("-", "_", ".", "_").Replace(name)
+}
+
 type ImportBuilder struct {
 	imports []Import
 	driver  opts.SQLDriver
@@ -22,17 +22,27 @@ func BuildStructs(
 		return nil
 	}
 	goTypeFormatter := gotype.NewGoTypeFormatter(gotypeTransformer, options)
+	defaultSchema := options.DefaultSchemaName(req)
 	for _, schema := range req.Catalog.Schemas {
 		if schema.Name == "pg_catalog" || schema.Name == "information_schema" {
 			continue
 		}
 		for _, table := range schema.Tables {
-			s := NewStruct(table, options, goTypeFormatter)
+			s := NewStruct(table, defaultSchema, options, goTypeFormatter)
 			structs = append(structs, *s)
 		}
 	}
 	if len(structs) > 0 {
 		sort.Slice(structs, func(i, j int) bool { return structs[i].Type().TypeName() < structs[j].Type().TypeName() })
 	}
+	tables := structs
+	for _, loader := range options.QueryLoaders {
+		for _, query := range req.Queries {
+			if query.Name == loader.Query {
+				structs = append(structs, *NewQueryStruct(query, loader, tables, defaultSchema, options, goTypeFormatter))
+				break
+			}
+		}
+	}
 	return structs
 }
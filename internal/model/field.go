@@ -14,6 +14,7 @@ type Field struct {
 	column  *plugin.Column
 
 	isPrimaryKey bool
+	isSelected   bool
 
 	// EmbedFields contains the embedded fields that require scanning.
 	embedFields []Field
@@ -50,3 +51,8 @@ func (f *Field) EmbedFields() []Field {
 func (f *Field) IsPrimaryKey() bool {
 	return f.isPrimaryKey
 }
+
+// IsSelected returns true if the column is fetched by the loader query.
+func (f *Field) IsSelected() bool {
+	return f.isSelected
+}
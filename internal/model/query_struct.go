@@ -0,0 +1,120 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/debugger84/sqlc-dataloader/internal/gotype"
+	"github.com/debugger84/sqlc-dataloader/internal/naming"
+	"github.com/debugger84/sqlc-dataloader/internal/opts"
+	"github.com/sqlc-dev/plugin-sdk-go/plugin"
+)
+
+// NewQueryStruct creates the struct of the result row of the sqlc query, e.g. ListBooksWithAuthorsRow.
+// The columns of sqlc.embed are the fields of the types of the embedded tables found in the tables.
+// The key of the loader is the field of the result column or of the column of an embedded table.
+// The struct belongs to the default schema.
+func NewQueryStruct(
+	query *plugin.Query,
+	loader opts.QueryLoader,
+	tables []Struct,
+	defaultSchema string,
+	options *opts.Options,
+	goTypeFormatter *gotype.GoTypeFormatter,
+) *Struct {
+	nameNormalizer := naming.NewNameNormalizer(options)
+	s := &Struct{
+		table: &plugin.Table{
+			Rel: &plugin.Identifier{Schema: defaultSchema, Name: query.Name},
+		},
+		schema:     defaultSchema,
+		tableName:  query.Name,
+		structName: query.Name + "Row",
+		query:      query,
+	}
+	structName := s.structName
+	if options.ModelImport != "" {
+		structName = options.ModelImport + "." + structName
+	}
+	s.goType = gotype.NewGoType(structName)
+
+	keyTable, keyColumn := loader.SplitKey()
+	seen := map[string]int{}
+	for i, column := range query.Columns {
+		if embed := column.GetEmbedTable(); embed != nil {
+			field, ok := s.embedField(column, tables, keyTable, keyColumn)
+			if ok {
+				s.fields = append(s.fields, field)
+			}
+			continue
+		}
+
+		colName := column.Name
+		if colName == "" {
+			colName = fmt.Sprintf("column_%d", i+1)
+		}
+		// Like sqlc, the duplicate names get the numeric suffix, e.g. ID and ID_2.
+		name := nameNormalizer.NormalizeGoType(colName)
+		if n := seen[name]; n > 0 {
+			seen[name]++
+			name = fmt.Sprintf("%s_%d", name, n+1)
+		} else {
+			seen[name] = 1
+		}
+		isPrimaryKey := keyTable == "" && colName == keyColumn && !s.hasPrimaryKey
+		s.hasPrimaryKey = s.hasPrimaryKey || isPrimaryKey
+		goType := goTypeFormatter.ToGoType(column)
+		s.fields = append(
+			s.fields, Field{
+				name:         name,
+				dBName:       colName,
+				goType:       &goType,
+				tags:         map[string]string{},
+				comment:      column.Comment,
+				column:       column,
+				isPrimaryKey: isPrimaryKey,
+				isSelected:   true,
+			},
+		)
+	}
+	return s
+}
+
+// embedField returns the field of the embedded table with the fields of all columns of the table.
+func (s *Struct) embedField(column *plugin.Column, tables []Struct, keyTable, keyColumn string) (Field, bool) {
+	embed := column.GetEmbedTable()
+	for _, t := range tables {
+		if t.RelName() != embed.Name || (embed.Schema != "" && t.Schema() != embed.Schema) {
+			continue
+		}
+		embedFields := make([]Field, len(t.Fields()))
+		for i, f := range t.Fields() {
+			f.isPrimaryKey = keyTable == embed.Name && f.DBName() == keyColumn && !s.hasPrimaryKey
+			s.hasPrimaryKey = s.hasPrimaryKey || f.isPrimaryKey
+			f.isSelected = true
+			embedFields[i] = f
+		}
+		return Field{
+			name:        t.Type().TypeName(),
+			dBName:      column.Name,
+			goType:      gotype.NewGoType(t.Type().TypeWithPackage()),
+			tags:        map[string]string{},
+			column:      column,
+			isSelected:  true,
+			embedFields: embedFields,
+		}, true
+	}
+	return Field{}, false
+}
+
+// IsQuery returns true if the struct is the result row of a sqlc query.
+func (s *Struct) IsQuery() bool {
+	return s.query != nil
+}
+
+// QueryText returns the SQL of the sqlc query. It is empty for the tables.
+func (s *Struct) QueryText() string {
+	if s.query == nil {
+		return ""
+	}
+	return s.query.Text
+}
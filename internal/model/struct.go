@@ -8,26 +8,35 @@ import (
 	"github.com/debugger84/sqlc-dataloader/internal/naming"
 	"github.com/debugger84/sqlc-dataloader/internal/opts"
 	"github.com/sqlc-dev/plugin-sdk-go/plugin"
-	"strings"
 )
 
 type Struct struct {
 	table         *plugin.Table
+	schema        string
 	tableName     string
 	structName    string
 	fields        []Field
 	hasPrimaryKey bool
+	isView        bool
 	goType        *gotype.GoType
+	// query is the sqlc query of the result row struct. It is nil for the tables.
+	query *plugin.Query
 }
 
+// NewStruct creates the struct of the table. The default schema is used for the tables without a schema.
 func NewStruct(
 	table *plugin.Table,
+	defaultSchema string,
 	options *opts.Options,
 	goTypeFormatter *gotype.GoTypeFormatter,
 ) *Struct {
 	nameNormalizer := naming.NewNameNormalizer(options)
 	s := &Struct{
-		table: table,
+		table:  table,
+		schema: table.Rel.GetSchema(),
+	}
+	if s.schema == "" {
+		s.schema = defaultSchema
 	}
 
 	s.initNames(table, options, nameNormalizer)
@@ -66,6 +75,25 @@ func (s *Struct) TableName() string {
 	return s.tableName
 }
 
+// Schema returns the schema of the table. The default schema is used if the table has no schema.
+func (s *Struct) Schema() string {
+	return s.schema
+}
+
+// RelName returns the name of the table as it is in the database.
+func (s *Struct) RelName() string {
+	return s.table.Rel.GetName()
+}
+
+// QualifiedName returns the name of the loaded relation in the format schema.tablename
+// or the name of the query for the result row of a sqlc query.
+func (s *Struct) QualifiedName() string {
+	if s.query != nil {
+		return s.query.Name
+	}
+	return s.schema + "." + s.RelName()
+}
+
 func (s *Struct) FullTableName() string {
 	schema := s.table.Rel.GetSchema()
 	tableName := s.table.Rel.GetName()
@@ -110,21 +138,30 @@ func (s *Struct) initFields(
 	normalizer *naming.NameNormalizer,
 	goTypeFormatter *gotype.GoTypeFormatter,
 ) {
-	primaryKeyColumn := "id"
-	for _, column := range options.PrimaryKeysColumns {
-		parts := strings.Split(column, ".")
-		if len(parts) == 2 && parts[0] == table.Rel.GetName() {
-			primaryKeyColumn = parts[1]
+	schema := s.schema
+	s.isView = options.IsView(schema, table.Rel.GetName())
+
+	hasIncludedColumns := false
+	for _, ref := range options.IncludeColumnRefs {
+		if ref.MatchesTable(schema, table.Rel.GetName()) {
+			hasIncludedColumns = true
 			break
 		}
 	}
 	for _, column := range table.Columns {
 		tags := map[string]string{}
 		isPrimaryKey := false
-		if column.Name == primaryKeyColumn {
+		if !s.hasPrimaryKey && options.IsPrimaryKeyColumn(schema, table.Rel.GetName(), column.Name) {
 			isPrimaryKey = true
 			s.hasPrimaryKey = true
 		}
+		isSelected := isPrimaryKey || isColumnSelected(
+			schema,
+			table.Rel.GetName(),
+			column.Name,
+			hasIncludedColumns,
+			options,
+		)
 		goType := goTypeFormatter.ToGoType(column)
 		s.fields = append(
 			s.fields, Field{
@@ -135,15 +172,75 @@ func (s *Struct) initFields(
 				comment:      column.Comment,
 				column:       column,
 				isPrimaryKey: isPrimaryKey,
+				isSelected:   isSelected,
 			},
 		)
 	}
 }
 
+// isColumnSelected checks the include_columns and exclude_columns options.
+func isColumnSelected(schema, table, column string, hasIncludedColumns bool, options *opts.Options) bool {
+	if hasIncludedColumns {
+		included := false
+		for _, ref := range options.IncludeColumnRefs {
+			if ref.Matches(schema, table, column) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+	for _, ref := range options.ExcludeColumnRefs {
+		if ref.Matches(schema, table, column) {
+			return false
+		}
+	}
+	return true
+}
+
 func (s *Struct) Fields() []Field {
 	return s.fields
 }
 
+// SelectedFields returns the fields fetched by the loader query.
+func (s *Struct) SelectedFields() []Field {
+	fields := make([]Field, 0, len(s.fields))
+	for _, f := range s.fields {
+		if f.IsSelected() {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// PrimaryKey returns the field of the key column. It returns nil if the struct has no primary key.
+// The key of an embedded table is named by the path from the struct, e.g. "Book.ID", and by the column
+// in the format tablename.colname, e.g. "books.id".
+func (s *Struct) PrimaryKey() *Field {
+	for i := range s.fields {
+		if s.fields[i].IsPrimaryKey() {
+			return &s.fields[i]
+		}
+	}
+	for _, embed := range s.fields {
+		for _, f := range embed.EmbedFields() {
+			if f.IsPrimaryKey() {
+				f.name = embed.Name() + "." + f.Name()
+				f.dBName = embed.Column().GetEmbedTable().GetName() + "." + f.DBName()
+				return &f
+			}
+		}
+	}
+	return nil
+}
+
 func (s *Struct) HasPrimaryKey() bool {
 	return s.hasPrimaryKey
 }
+
+// IsView returns true if the struct is built from a view or a materialized view.
+func (s *Struct) IsView() bool {
+	return s.isView
+}
@@ -0,0 +1,108 @@
+package opts
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sqlc-dev/plugin-sdk-go/pattern"
+)
+
+// TableRef is a parsed table reference in the format [schema.]tablename.
+// Both parts may contain the "*" and "?" wildcards.
+type TableRef struct {
+	// Schema is nil if the reference matches tables in any schema.
+	Schema *pattern.Match
+	Rel    *pattern.Match
+}
+
+func parseTableRef(ref string, defaultSchema string) (TableRef, error) {
+	var r TableRef
+	var err error
+	parts := strings.Split(ref, ".")
+	switch len(parts) {
+	case 1:
+		if r.Rel, err = pattern.MatchCompile(parts[0]); err != nil {
+			return r, err
+		}
+		if defaultSchema != "" {
+			if r.Schema, err = pattern.MatchCompile(defaultSchema); err != nil {
+				return r, err
+			}
+		}
+	case 2:
+		if r.Rel, err = pattern.MatchCompile(parts[1]); err != nil {
+			return r, err
+		}
+		if r.Schema, err = pattern.MatchCompile(parts[0]); err != nil {
+			return r, err
+		}
+	default:
+		return r, fmt.Errorf("table specifier %q is not the proper format, expected '[schema.]tablename'", ref)
+	}
+	return r, nil
+}
+
+func parseTableRefs(refs []string, defaultSchema string) ([]TableRef, error) {
+	parsed := make([]TableRef, 0, len(refs))
+	for _, ref := range refs {
+		r, err := parseTableRef(ref, defaultSchema)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, r)
+	}
+	return parsed, nil
+}
+
+// Matches returns true if the reference points to the given table.
+func (r TableRef) Matches(schema, rel string) bool {
+	if r.Schema != nil && !r.Schema.MatchString(schema) {
+		return false
+	}
+	return r.Rel.MatchString(rel)
+}
+
+// ColumnRef is a parsed column reference in the format [schema.]tablename.colname.
+// All parts may contain the "*" and "?" wildcards.
+type ColumnRef struct {
+	TableRef
+	Column *pattern.Match
+}
+
+func parseColumnRef(ref string, defaultSchema string) (ColumnRef, error) {
+	var r ColumnRef
+	lastDot := strings.LastIndex(ref, ".")
+	if lastDot == -1 || strings.Count(ref, ".") > 2 {
+		return r, fmt.Errorf("column specifier %q is not the proper format, expected '[schema.]tablename.colname'", ref)
+	}
+	var err error
+	if r.TableRef, err = parseTableRef(ref[:lastDot], defaultSchema); err != nil {
+		return r, err
+	}
+	if r.Column, err = pattern.MatchCompile(ref[lastDot+1:]); err != nil {
+		return r, err
+	}
+	return r, nil
+}
+
+func parseColumnRefs(refs []string, defaultSchema string) ([]ColumnRef, error) {
+	parsed := make([]ColumnRef, 0, len(refs))
+	for _, ref := range refs {
+		r, err := parseColumnRef(ref, defaultSchema)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, r)
+	}
+	return parsed, nil
+}
+
+// MatchesTable returns true if the reference points to a column of the given table.
+func (r ColumnRef) MatchesTable(schema, table string) bool {
+	return r.TableRef.Matches(schema, table)
+}
+
+// Matches returns true if the reference points to the given column.
+func (r ColumnRef) Matches(schema, table, column string) bool {
+	return r.MatchesTable(schema, table) && r.Column.MatchString(column)
+}
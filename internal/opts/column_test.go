@@ -0,0 +1,87 @@
+package opts
+
+import (
+	"testing"
+)
+
+func TestTableRefMatches(t *testing.T) {
+	for _, test := range []struct {
+		ref    string
+		schema string
+		rel    string
+		match  bool
+	}{
+		{"public.authors", "public", "authors", true},
+		{"public.authors", "other", "authors", false},
+		{"authors", "public", "authors", true},
+		{"authors", "other", "authors", false},
+		{"public.*", "public", "books", true},
+		{"public.audit_*", "public", "audit_log", true},
+		{"public.audit_*", "public", "authors", false},
+		{"*.authors", "other", "authors", true},
+		{"public.author?", "public", "authors", true},
+	} {
+		tt := test
+		t.Run(tt.ref+" "+tt.schema+"."+tt.rel, func(t *testing.T) {
+			ref, err := parseTableRef(tt.ref, "public")
+			if err != nil {
+				t.Fatalf("table ref parsing failed; %s", err)
+			}
+			if got := ref.Matches(tt.schema, tt.rel); got != tt.match {
+				t.Errorf("expected %v; got %v", tt.match, got)
+			}
+		})
+	}
+}
+
+func TestColumnRefMatches(t *testing.T) {
+	for _, test := range []struct {
+		ref    string
+		schema string
+		rel    string
+		column string
+		match  bool
+	}{
+		{"public.authors.bio", "public", "authors", "bio", true},
+		{"authors.bio", "public", "authors", "bio", true},
+		{"authors.bio", "public", "authors", "name", false},
+		{"*.payload_*", "public", "books", "payload_json", true},
+		{"public.*.payload", "other", "books", "payload", false},
+	} {
+		tt := test
+		t.Run(tt.ref, func(t *testing.T) {
+			ref, err := parseColumnRef(tt.ref, "public")
+			if err != nil {
+				t.Fatalf("column ref parsing failed; %s", err)
+			}
+			if got := ref.Matches(tt.schema, tt.rel, tt.column); got != tt.match {
+				t.Errorf("expected %v; got %v", tt.match, got)
+			}
+		})
+	}
+	for _, ref := range []string{"authors", "catalog.public.authors.id"} {
+		if _, err := parseColumnRef(ref, "public"); err == nil {
+			t.Errorf("expected parse of %q to fail; got nil", ref)
+		}
+	}
+}
+
+func TestIsTableIncluded(t *testing.T) {
+	options := Options{}
+	var err error
+	if options.IncludeTableRefs, err = parseTableRefs([]string{"public.*"}, "public"); err != nil {
+		t.Fatal(err)
+	}
+	if options.ExcludeTableRefs, err = parseTableRefs([]string{"public.audit_*"}, "public"); err != nil {
+		t.Fatal(err)
+	}
+	if !options.IsTableIncluded("public", "authors") {
+		t.Errorf("expected public.authors to be included")
+	}
+	if options.IsTableIncluded("public", "audit_log") {
+		t.Errorf("expected public.audit_log to be excluded")
+	}
+	if options.IsTableIncluded("other", "authors") {
+		t.Errorf("expected other.authors to be excluded")
+	}
+}
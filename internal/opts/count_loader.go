@@ -0,0 +1,42 @@
+package opts
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CountLoader is the loader of the numbers of the rows of a table by the values of a column,
+// e.g. the numbers of the books by the author ids.
+type CountLoader struct {
+	// Column is the column the rows are counted by in the format [schema.]tablename.colname, e.g. "books.author_id".
+	Column string `json:"column" yaml:"column"`
+	// Name is the name of the loader without the "Loader" suffix, e.g. "BooksCountByAuthorID".
+	// By default, it is the table and the column in camel case joined by "CountBy".
+	Name string `json:"name,omitempty" yaml:"name"`
+	// Filter is the static SQL condition of the counted rows, e.g. "deleted_at IS NULL".
+	Filter string `json:"filter,omitempty" yaml:"filter"`
+
+	// Table is the counted table in the format [schema.]tablename.
+	Table string `json:"-" yaml:"-"`
+	// KeyColumn is the name of the column, e.g. "author_id".
+	KeyColumn string    `json:"-" yaml:"-"`
+	ColumnRef ColumnRef `json:"-" yaml:"-"`
+}
+
+func (c *CountLoader) parse(defaultSchema string) error {
+	var err error
+	if c.ColumnRef, err = parseColumnRef(c.Column, defaultSchema); err != nil {
+		return fmt.Errorf("invalid count_loaders column: %w", err)
+	}
+	lastDot := strings.LastIndex(c.Column, ".")
+	c.Table, c.KeyColumn = c.Column[:lastDot], c.Column[lastDot+1:]
+	return nil
+}
+
+func validateCountLoader(option string, c CountLoader, tables []catalogTable) []error {
+	errs := validateColumnRef(option+".column", c.Column, c.ColumnRef, tables)
+	if strings.Contains(c.Filter, ";") {
+		errs = append(errs, fmt.Errorf("%s.filter: the filter must be one SQL condition without \";\"", option))
+	}
+	return errs
+}
@@ -0,0 +1,34 @@
+package opts
+
+import (
+	"fmt"
+)
+
+// ForeignKey is a column referencing the key column of a table.
+// The catalog of sqlc has no foreign keys, so they are configured explicitly.
+type ForeignKey struct {
+	// Column is the foreign key column in the format [schema.]tablename.colname, e.g. "books.author_id".
+	Column string `json:"column" yaml:"column"`
+	// References is the referenced table in the format [schema.]tablename, e.g. "authors".
+	References string `json:"references" yaml:"references"`
+	// Name is the name of the referenced object in the generated helpers, e.g. "Author" in ResolveBookAuthor.
+	// By default, it is the column name without the "_id" suffix in camel case.
+	Name string `json:"name,omitempty" yaml:"name"`
+
+	ColumnRef     ColumnRef `json:"-" yaml:"-"`
+	ReferencesRef TableRef  `json:"-" yaml:"-"`
+}
+
+func (fk *ForeignKey) parse(defaultSchema string) error {
+	var err error
+	if fk.ColumnRef, err = parseColumnRef(fk.Column, defaultSchema); err != nil {
+		return fmt.Errorf("invalid foreign_keys column: %w", err)
+	}
+	if fk.References == "" {
+		return fmt.Errorf("foreign key %q: missing referenced table", fk.Column)
+	}
+	if fk.ReferencesRef, err = parseTableRef(fk.References, defaultSchema); err != nil {
+		return fmt.Errorf("invalid foreign_keys references: %w", err)
+	}
+	return nil
+}
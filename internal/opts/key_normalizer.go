@@ -0,0 +1,35 @@
+package opts
+
+import (
+	"fmt"
+	"go/token"
+	"slices"
+	"strings"
+)
+
+// KeyNormalizer returns the normalisation function of the keys of the database type, e.g. "citext",
+// in the format [import/path.]Func. It is empty if the keys of the type are not normalised by a custom function.
+func (o *Options) KeyNormalizer(dbType string) string {
+	return o.KeyNormalizers[strings.TrimPrefix(dbType, "pg_catalog.")]
+}
+
+func validateKeyNormalizers(normalizers map[string]string) []error {
+	var errs []error
+	dbTypes := make([]string, 0, len(normalizers))
+	for dbType := range normalizers {
+		dbTypes = append(dbTypes, dbType)
+	}
+	slices.Sort(dbTypes)
+	for _, dbType := range dbTypes {
+		fn := normalizers[dbType]
+		name := fn[strings.LastIndex(fn, ".")+1:]
+		if !token.IsIdentifier(name) || strings.HasSuffix(fn, "/"+name) {
+			errs = append(errs, fmt.Errorf(
+				"key_normalizers.%s: invalid function %q, expected a value like \"github.com/acme/keys.Normalize\"",
+				dbType,
+				fn,
+			))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,85 @@
+package opts
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	// OutputLayoutPerTable renders each loader to its own file named by the loader_filename pattern.
+	OutputLayoutPerTable = "per_table"
+	// OutputLayoutSingleFile renders all loaders and the loader factory to one file.
+	OutputLayoutSingleFile = "single_file"
+	// OutputLayoutPerSchema renders the loaders of each schema to one file.
+	OutputLayoutPerSchema = "per_schema"
+
+	DefaultLoaderFilename = "{name}.go"
+	// ModelPackageLoaderFilename is the default file name of a loader generated to the package of the models,
+	// the suffix separates the loaders from the model files.
+	ModelPackageLoaderFilename = "{name}_loader.go"
+	SingleFileName             = "loaders.go"
+	PerSchemaFilename          = "{schema}_loaders.go"
+)
+
+var validOutputLayouts = []string{OutputLayoutPerTable, OutputLayoutSingleFile, OutputLayoutPerSchema}
+
+// Layout returns the output layout, per_table by default.
+func (o *Options) Layout() string {
+	if o.OutputLayout == "" {
+		return OutputLayoutPerTable
+	}
+	return o.OutputLayout
+}
+
+// LoaderFilename returns the name of the file of the table loader in the per_table layout.
+// The name is the snake case name of the model, e.g. "author" for the Author model.
+// Without the pattern, the loaders in the package of the models are named "author_loader.go"
+// and the loaders in their own package are named "author.go".
+func (o *Options) LoaderFilename(schema, rel, name string, modelPackage bool) string {
+	pattern := o.LoaderFilenamePattern
+	if pattern == "" {
+		pattern = DefaultLoaderFilename
+		if modelPackage {
+			pattern = ModelPackageLoaderFilename
+		}
+	}
+	return strings.NewReplacer("{schema}", schema, "{table}", rel, "{name}", name).Replace(pattern)
+}
+
+// SchemaFilename returns the name of the file of the schema loaders in the per_schema layout.
+func SchemaFilename(schema string) string {
+	return strings.ReplaceAll(PerSchemaFilename, "{schema}", schema)
+}
+
+func validateLayout(o *Options) []error {
+	var errs []error
+	if o.OutputLayout != "" && !slices.Contains(validOutputLayouts, o.OutputLayout) {
+		errs = append(
+			errs,
+			fmt.Errorf(
+				"output_layout: unknown layout %q, expected one of %s%s",
+				o.OutputLayout,
+				strings.Join(validOutputLayouts, ", "),
+				didYouMean(o.OutputLayout, validOutputLayouts),
+			),
+		)
+	}
+	pattern := o.LoaderFilenamePattern
+	if pattern == "" {
+		return errs
+	}
+	if o.Layout() != OutputLayoutPerTable {
+		errs = append(errs, fmt.Errorf("loader_filename: the pattern is used only by the %q layout", OutputLayoutPerTable))
+	}
+	if !strings.Contains(pattern, "{name}") && !strings.Contains(pattern, "{table}") {
+		errs = append(errs, fmt.Errorf("loader_filename: the pattern %q must contain {name} or {table}", pattern))
+	}
+	if strings.ContainsAny(pattern, `/\`) {
+		errs = append(errs, fmt.Errorf("loader_filename: the pattern %q cannot contain a directory", pattern))
+	}
+	if !strings.HasSuffix(pattern, ".go") || strings.HasSuffix(pattern, "_test.go") {
+		errs = append(errs, fmt.Errorf("loader_filename: the pattern %q must have the .go extension", pattern))
+	}
+	return errs
+}
@@ -0,0 +1,91 @@
+package opts
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var manyToManyRelation = regexp.MustCompile(`^\s*(\S+)\s*->\s*([^\s(]+)\s*\(\s*([^\s,]+)\s*,\s*([^\s)]+)\s*\)\s*->\s*(\S+)\s*$`)
+
+// ManyToMany is the relation of two tables through a join table, e.g. posts and tags through post_tags.
+// The loader of the relation loads the rows of the target table by the keys of the source table.
+type ManyToMany struct {
+	// Relation is the relation in the format "source.key -> join(source_column, target_column) -> target.key",
+	// e.g. "posts.id -> post_tags(post_id, tag_id) -> tags.id". The tables are in the format [schema.]tablename.
+	Relation string `json:"relation" yaml:"relation"`
+	// Name is the name of the loader without the "Loader" suffix, e.g. "TagsByPostID".
+	// By default, it is the target table and the source column of the join table in camel case.
+	Name string `json:"name,omitempty" yaml:"name"`
+	// JoinColumns are the columns of the join table returned alongside each target row, e.g. "position".
+	JoinColumns []string `json:"join_columns,omitempty" yaml:"join_columns"`
+
+	// Source is the key column of the source table in the format [schema.]tablename.colname.
+	Source string `json:"-" yaml:"-"`
+	// Join is the join table in the format [schema.]tablename.
+	Join string `json:"-" yaml:"-"`
+	// JoinSource is the column of the join table referencing the source table, e.g. "post_id".
+	JoinSource string `json:"-" yaml:"-"`
+	// JoinTarget is the column of the join table referencing the target table, e.g. "tag_id".
+	JoinTarget string `json:"-" yaml:"-"`
+	// Target is the key column of the target table in the format [schema.]tablename.colname.
+	Target string `json:"-" yaml:"-"`
+	// TargetKey is the key column of the target table referenced by the join table, e.g. "id".
+	TargetKey string `json:"-" yaml:"-"`
+
+	SourceRef     ColumnRef `json:"-" yaml:"-"`
+	JoinRef       TableRef  `json:"-" yaml:"-"`
+	JoinSourceRef ColumnRef `json:"-" yaml:"-"`
+	JoinTargetRef ColumnRef `json:"-" yaml:"-"`
+	TargetRef     ColumnRef `json:"-" yaml:"-"`
+}
+
+func (m *ManyToMany) parse(defaultSchema string) error {
+	parts := manyToManyRelation.FindStringSubmatch(m.Relation)
+	if parts == nil {
+		return fmt.Errorf(
+			"many_to_many relation %q is not the proper format, expected 'source.key -> join(source_column, target_column) -> target.key'",
+			m.Relation,
+		)
+	}
+	m.Source, m.Join, m.JoinSource, m.JoinTarget, m.Target = parts[1], parts[2], parts[3], parts[4], parts[5]
+	m.TargetKey = m.Target[strings.LastIndex(m.Target, ".")+1:]
+	var err error
+	if m.SourceRef, err = parseColumnRef(m.Source, defaultSchema); err != nil {
+		return fmt.Errorf("invalid many_to_many source: %w", err)
+	}
+	if m.JoinRef, err = parseTableRef(m.Join, defaultSchema); err != nil {
+		return fmt.Errorf("invalid many_to_many join table: %w", err)
+	}
+	if m.JoinSourceRef, err = parseColumnRef(m.Join+"."+m.JoinSource, defaultSchema); err != nil {
+		return fmt.Errorf("invalid many_to_many join column: %w", err)
+	}
+	if m.JoinTargetRef, err = parseColumnRef(m.Join+"."+m.JoinTarget, defaultSchema); err != nil {
+		return fmt.Errorf("invalid many_to_many join column: %w", err)
+	}
+	if m.TargetRef, err = parseColumnRef(m.Target, defaultSchema); err != nil {
+		return fmt.Errorf("invalid many_to_many target: %w", err)
+	}
+	return nil
+}
+
+func validateManyToMany(option string, m ManyToMany, tables []catalogTable) []error {
+	option += ".relation"
+	errs := validateColumnRef(option, m.Source, m.SourceRef, tables)
+	if matchTables(m.JoinRef, tables) == nil {
+		errs = append(errs, tableNotFound(option, m.Join, tables))
+	} else {
+		errs = append(errs, validateColumnRef(option, m.Join+"."+m.JoinSource, m.JoinSourceRef, tables)...)
+		errs = append(errs, validateColumnRef(option, m.Join+"."+m.JoinTarget, m.JoinTargetRef, tables)...)
+		for _, column := range m.JoinColumns {
+			ref, err := parseColumnRef(m.Join+"."+column, "")
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", option, err))
+				continue
+			}
+			ref.TableRef = m.JoinRef
+			errs = append(errs, validateColumnRef(option, m.Join+"."+column, ref, tables)...)
+		}
+	}
+	return append(errs, validateColumnRef(option, m.Target, m.TargetRef, tables)...)
+}
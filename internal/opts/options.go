@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"maps"
+	"path"
 	"path/filepath"
 
 	"github.com/sqlc-dev/plugin-sdk-go/plugin"
@@ -15,10 +16,12 @@ type Cache struct {
 	// Type is the type of the cache. Available types: memory, lru, no-cache.
 	Type string `json:"type" yaml:"type"`
 	// Ttl is the time to live for the items in cache. It is used only for lru cache.
-	// Example values: "1m", "1h", "1d".
+	// Example values: "1m", "1h", "1d", "1w", "1d3h20m40s".
 	Ttl string `json:"ttl" yaml:"ttl"`
 	// Size is the size of the cache. It is used only for lru cache.
 	Size int `json:"size" yaml:"size"`
+
+	TableRef TableRef `json:"-" yaml:"-"`
 }
 
 type Options struct {
@@ -34,12 +37,98 @@ type Options struct {
 	SqlPackage                  string            `json:"sql_package" yaml:"sql_package"`
 	EmitPointersForNullTypes    bool              `json:"emit_pointers_for_null_types" yaml:"emit_pointers_for_null_types"`
 
+	// PrimaryKeysColumns are the key columns of the loaders in the format [schema.]tablename.colname.
 	PrimaryKeysColumns []string `json:"primary_keys_columns" yaml:"primary_keys_columns"`
 	ModelImport        string   `json:"model_import" yaml:"model_import"`
 	Cache              []Cache  `json:"cache" yaml:"cache"`
 	ExcludeTables      []string `json:"exclude_tables" yaml:"exclude_tables"`
+	// IncludeTables are the tables in the format [schema.]tablename the loaders are generated for.
+	// If it is empty, the loaders are generated for all tables.
+	IncludeTables []string `json:"include_tables,omitempty" yaml:"include_tables"`
+	// ExcludeColumns are the columns in the format [schema.]tablename.colname
+	// that are not selected by loaders. The model fields stay at their zero values.
+	ExcludeColumns []string `json:"exclude_columns,omitempty" yaml:"exclude_columns"`
+	// IncludeColumns are the columns in the format [schema.]tablename.colname
+	// that are selected by loaders. If a table has at least one included column,
+	// all other columns of the table except the primary key are not selected.
+	IncludeColumns []string `json:"include_columns,omitempty" yaml:"include_columns"`
+
+	// Views are the views and materialized views with explicitly configured keys.
+	Views []View `json:"views,omitempty" yaml:"views"`
+	// ExcludeViews skips the loaders for the views and materialized views recognised by IsView.
+	// The sqlc catalog does not distinguish the views from the tables,
+	// so the views without the "_mv" suffix are skipped only if they are listed in the views option.
+	ExcludeViews bool `json:"exclude_views,omitempty" yaml:"exclude_views"`
+
+	// TemplatesDir is the directory with the templates overriding the built-in ones.
+	// The templates are named as the built-in ones: dataloader.tmpl and loader_factory.tmpl.
+	TemplatesDir string `json:"templates_dir,omitempty" yaml:"templates_dir"`
+	// DataLoaderTemplate is the path to the template overriding the built-in dataloader.tmpl.
+	DataLoaderTemplate string `json:"dataloader_template,omitempty" yaml:"dataloader_template"`
+	// LoaderFactoryTemplate is the path to the template overriding the built-in loader_factory.tmpl.
+	LoaderFactoryTemplate string `json:"loader_factory_template,omitempty" yaml:"loader_factory_template"`
+
+	// EmitFakes enables the generation of the in-memory fake loaders for tests.
+	EmitFakes bool `json:"emit_fakes,omitempty" yaml:"emit_fakes"`
+	// FakesPackage is the package name of the fake loaders. By default, it is the package name with the "test" suffix.
+	FakesPackage string `json:"fakes_package,omitempty" yaml:"fakes_package"`
+
+	// VerifyOutput enables the type-checking of the generated code against the stubbed model and driver packages.
+	VerifyOutput bool `json:"verify_output,omitempty" yaml:"verify_output"`
+
+	// OutputLayout is the layout of the generated files: per_table (by default), single_file or per_schema.
+	OutputLayout string `json:"output_layout,omitempty" yaml:"output_layout"`
+	// LoaderFilenamePattern is the file name of a loader in the per_table layout,
+	// "{name}_loader.go" in the package of the models and "{name}.go" in a separate package by default.
+	// The {name} placeholder is the snake case name of the model, {table} and {schema} are the names of the table and its schema.
+	LoaderFilenamePattern string `json:"loader_filename,omitempty" yaml:"loader_filename"`
+
+	// EmitExists enables the generation of the Exists and ExistsMany methods of the table loaders
+	// selecting only the keys of the rows.
+	EmitExists bool `json:"emit_exists,omitempty" yaml:"emit_exists"`
+
+	// EmitManifest enables the generation of the dataloader_manifest.json file describing the generated loaders.
+	EmitManifest bool `json:"emit_manifest,omitempty" yaml:"emit_manifest"`
 
-	InitialismsMap map[string]struct{} `json:"-" yaml:"-"`
+	// Gqlgen enables the generation of the gqlgen middleware and the resolver helpers of the foreign keys.
+	Gqlgen bool `json:"gqlgen,omitempty" yaml:"gqlgen"`
+	// ForeignKeys are the columns referencing the key columns of other tables.
+	ForeignKeys []ForeignKey `json:"foreign_keys,omitempty" yaml:"foreign_keys"`
+
+	// ManyToMany are the relations of the tables through the join tables, e.g. posts and tags through post_tags.
+	ManyToMany []ManyToMany `json:"many_to_many,omitempty" yaml:"many_to_many"`
+	// CountLoaders are the loaders of the numbers of the rows by the values of the columns, e.g. the books by the authors.
+	CountLoaders []CountLoader `json:"count_loaders,omitempty" yaml:"count_loaders"`
+	// QueryLoaders are the loaders over the result rows of the sqlc queries, e.g. the queries with sqlc.embed.
+	QueryLoaders []QueryLoader `json:"query_loaders,omitempty" yaml:"query_loaders"`
+
+	// ScanMode is the scanning of the rows: positional (by default) or by_name. The by_name mode requires pgx/v5.
+	ScanMode string `json:"scan_mode,omitempty" yaml:"scan_mode"`
+	// SelectAllColumns selects the rows by "SELECT *" instead of the list of the columns. It requires the by_name scan mode.
+	SelectAllColumns bool `json:"select_all_columns,omitempty" yaml:"select_all_columns"`
+
+	// MaxKeysPerQuery splits the batches with more keys into several queries. There is no limit if it is 0.
+	MaxKeysPerQuery int `json:"max_keys_per_query,omitempty" yaml:"max_keys_per_query"`
+	// QueryConcurrency is the number of the parallel queries of one split batch, 4 by default.
+	QueryConcurrency int `json:"query_concurrency,omitempty" yaml:"query_concurrency"`
+	// QueryTimeout bounds the context of each query of a batch, e.g. "500ms" or "2s". There is no timeout by default.
+	QueryTimeout string `json:"query_timeout,omitempty" yaml:"query_timeout"`
+	// Retry retries the failed queries of the transient errors, e.g. serialization failures. The queries are not retried by default.
+	Retry *Retry `json:"retry,omitempty" yaml:"retry"`
+	// DuplicateKeys is the handling of the keys with more than one row: warn (by default), error or first.
+	DuplicateKeys string `json:"duplicate_keys,omitempty" yaml:"duplicate_keys"`
+	// KeyNormalizers are the functions normalising the keys by the database types of the key columns,
+	// e.g. {"citext": "github.com/acme/keys.Fold"}. A function has the key type as the parameter and the result.
+	KeyNormalizers map[string]string `json:"key_normalizers,omitempty" yaml:"key_normalizers"`
+	// Tables override the runtime settings of the loaders of the matching tables.
+	Tables []TableOptions `json:"tables,omitempty" yaml:"tables"`
+
+	InitialismsMap       map[string]struct{} `json:"-" yaml:"-"`
+	ExcludeColumnRefs    []ColumnRef         `json:"-" yaml:"-"`
+	IncludeColumnRefs    []ColumnRef         `json:"-" yaml:"-"`
+	ExcludeTableRefs     []TableRef          `json:"-" yaml:"-"`
+	IncludeTableRefs     []TableRef          `json:"-" yaml:"-"`
+	PrimaryKeyColumnRefs []ColumnRef         `json:"-" yaml:"-"`
 }
 
 type GlobalOptions struct {
@@ -70,6 +159,7 @@ func Parse(req *plugin.GenerateRequest) (*Options, error) {
 
 func parseOpts(req *plugin.GenerateRequest) (*Options, error) {
 	var options Options
+	var err error
 	if len(req.PluginOptions) == 0 {
 		return &options, nil
 	}
@@ -101,6 +191,57 @@ func parseOpts(req *plugin.GenerateRequest) (*Options, error) {
 		options.InitialismsMap[initial] = struct{}{}
 	}
 
+	if options.EmitFakes && options.FakesPackage == "" {
+		options.FakesPackage = options.Package + "test"
+	}
+
+	schema := options.DefaultSchemaName(req)
+	if options.ExcludeColumnRefs, err = parseColumnRefs(options.ExcludeColumns, schema); err != nil {
+		return nil, fmt.Errorf("invalid exclude_columns: %w", err)
+	}
+	if options.IncludeColumnRefs, err = parseColumnRefs(options.IncludeColumns, schema); err != nil {
+		return nil, fmt.Errorf("invalid include_columns: %w", err)
+	}
+	if options.ExcludeTableRefs, err = parseTableRefs(options.ExcludeTables, schema); err != nil {
+		return nil, fmt.Errorf("invalid exclude_tables: %w", err)
+	}
+	if options.IncludeTableRefs, err = parseTableRefs(options.IncludeTables, schema); err != nil {
+		return nil, fmt.Errorf("invalid include_tables: %w", err)
+	}
+	if options.PrimaryKeyColumnRefs, err = parseColumnRefs(options.PrimaryKeysColumns, schema); err != nil {
+		return nil, fmt.Errorf("invalid primary_keys_columns: %w", err)
+	}
+	for i := range options.Cache {
+		if options.Cache[i].TableRef, err = parseTableRef(options.Cache[i].Table, schema); err != nil {
+			return nil, fmt.Errorf("invalid cache table: %w", err)
+		}
+	}
+	for i := range options.Tables {
+		if options.Tables[i].TableRef, err = parseTableRef(options.Tables[i].Table, schema); err != nil {
+			return nil, fmt.Errorf("invalid tables table: %w", err)
+		}
+	}
+	for i := range options.Views {
+		if err := options.Views[i].parse(schema); err != nil {
+			return nil, err
+		}
+	}
+	for i := range options.ForeignKeys {
+		if err := options.ForeignKeys[i].parse(schema); err != nil {
+			return nil, err
+		}
+	}
+	for i := range options.ManyToMany {
+		if err := options.ManyToMany[i].parse(schema); err != nil {
+			return nil, err
+		}
+	}
+	for i := range options.CountLoaders {
+		if err := options.CountLoaders[i].parse(schema); err != nil {
+			return nil, err
+		}
+	}
+
 	return &options, nil
 }
 
@@ -120,9 +261,73 @@ func parseGlobalOpts(req *plugin.GenerateRequest) (*GlobalOptions, error) {
 	return &options, nil
 }
 
-func ValidateOpts(opts *Options) error {
+// IsTableIncluded checks the include_tables and exclude_tables options.
+func (o *Options) IsTableIncluded(schema, rel string) bool {
+	if len(o.IncludeTableRefs) > 0 {
+		included := false
+		for _, ref := range o.IncludeTableRefs {
+			if ref.Matches(schema, rel) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+	for _, ref := range o.ExcludeTableRefs {
+		if ref.Matches(schema, rel) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsPrimaryKeyColumn returns true if the column can be used as the key of the table loader.
+// The key is configured by the views and primary_keys_columns options. By default, it is the "id" column.
+func (o *Options) IsPrimaryKeyColumn(schema, rel, column string) bool {
+	if view, ok := o.FindView(schema, rel); ok {
+		return column == view.Key
+	}
+	for _, ref := range o.PrimaryKeyColumnRefs {
+		if ref.MatchesTable(schema, rel) {
+			return ref.Column.MatchString(column)
+		}
+	}
+	return column == "id"
+}
+
+// FindCache returns the first cache configuration matching the table.
+func (o *Options) FindCache(schema, rel string) (Cache, bool) {
+	for _, cache := range o.Cache {
+		if cache.TableRef.Matches(schema, rel) {
+			return cache, true
+		}
+	}
+	return Cache{}, false
+}
 
-	return nil
+// LoaderImport returns the import path of the package of the generated loaders.
+// It is empty if the model_import option is not set.
+func (o *Options) LoaderImport() string {
+	if o.ModelImport == "" {
+		return ""
+	}
+	if path.Base(o.ModelImport) == o.Package {
+		return o.ModelImport
+	}
+	return o.ModelImport + "/" + o.Package
+}
+
+// DefaultSchemaName returns the schema that is used for table names without a schema.
+func (o *Options) DefaultSchemaName(req *plugin.GenerateRequest) string {
+	if o.DefaultSchema != "" {
+		return o.DefaultSchema
+	}
+	if req != nil && req.Catalog != nil && req.Catalog.DefaultSchema != "" {
+		return req.Catalog.DefaultSchema
+	}
+	return "public"
 }
 
 func (o *Options) Driver() SQLDriver {
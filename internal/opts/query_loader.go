@@ -0,0 +1,90 @@
+package opts
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sqlc-dev/plugin-sdk-go/plugin"
+)
+
+// QueryLoader is a loader over the result rows of a sqlc query, e.g. a query joining the tables with sqlc.embed.
+// The query must have one parameter, the array of the keys, e.g. "WHERE books.id = ANY(@ids::uuid[])".
+type QueryLoader struct {
+	// Query is the name of the sqlc query, e.g. "ListBooksWithAuthors".
+	Query string `json:"query" yaml:"query"`
+	// Key is the result column used to batch the requests, e.g. "id".
+	// The column of an embedded table is in the format tablename.colname, e.g. "books.id" for sqlc.embed(books).
+	Key string `json:"key" yaml:"key"`
+}
+
+// SplitKey returns the embedded table and the column of the key. The table is empty for a plain result column.
+func (q QueryLoader) SplitKey() (string, string) {
+	if table, column, ok := strings.Cut(q.Key, "."); ok {
+		return table, column
+	}
+	return "", q.Key
+}
+
+func validateQueryLoader(option string, q QueryLoader, queries []*plugin.Query, tables []catalogTable) []error {
+	var query *plugin.Query
+	names := make([]string, 0, len(queries))
+	for _, candidate := range queries {
+		names = append(names, candidate.Name)
+		if candidate.Name == q.Query {
+			query = candidate
+		}
+	}
+	if query == nil {
+		return []error{fmt.Errorf("%s.query: query %q not found%s", option, q.Query, didYouMean(q.Query, names))}
+	}
+
+	var errs []error
+	if len(query.Params) != 1 {
+		errs = append(errs, fmt.Errorf(
+			"%s.query: the query %q must have one parameter, the array of the keys, got %d",
+			option,
+			q.Query,
+			len(query.Params),
+		))
+	}
+	columns := queryResultColumns(query, tables)
+	found := false
+	for _, column := range columns {
+		if column == q.Key {
+			found = true
+			break
+		}
+	}
+	if !found {
+		errs = append(errs, fmt.Errorf(
+			"%s.key: column %q not found in the result of %q%s",
+			option,
+			q.Key,
+			q.Query,
+			didYouMean(q.Key, columns),
+		))
+	}
+	return errs
+}
+
+// queryResultColumns returns the result columns of the query.
+// The columns of the embedded tables are in the format tablename.colname.
+func queryResultColumns(query *plugin.Query, tables []catalogTable) []string {
+	var columns []string
+	for _, column := range query.Columns {
+		embed := column.GetEmbedTable()
+		if embed == nil {
+			columns = append(columns, column.Name)
+			continue
+		}
+		for _, table := range tables {
+			if table.rel == embed.Name && (embed.Schema == "" || table.schema == embed.Schema) {
+				for _, c := range table.columns {
+					columns = append(columns, embed.Name+"."+c)
+				}
+				break
+			}
+		}
+	}
+	return columns
+}
@@ -0,0 +1,48 @@
+package opts
+
+import (
+	"fmt"
+	"time"
+)
+
+// Retry is the retry policy of the loaders. The zero values are replaced by the defaults of the runtime library.
+type Retry struct {
+	// MaxAttempts is the number of the attempts of a query including the first one, 3 by default.
+	MaxAttempts int `json:"max_attempts,omitempty" yaml:"max_attempts"`
+	// BaseDelay is the delay before the first retry, e.g. "50ms". It doubles with each attempt.
+	BaseDelay string `json:"base_delay,omitempty" yaml:"base_delay"`
+	// MaxDelay is the maximal delay between the attempts, e.g. "1s".
+	MaxDelay string `json:"max_delay,omitempty" yaml:"max_delay"`
+}
+
+// BaseDelayDuration returns the parsed base delay.
+func (r Retry) BaseDelayDuration() (time.Duration, error) {
+	return ParseTTL(r.BaseDelay)
+}
+
+// MaxDelayDuration returns the parsed max delay.
+func (r Retry) MaxDelayDuration() (time.Duration, error) {
+	return ParseTTL(r.MaxDelay)
+}
+
+func validateRetry(option string, retry *Retry) []error {
+	if retry == nil {
+		return nil
+	}
+	var errs []error
+	if retry.MaxAttempts < 0 {
+		errs = append(errs, fmt.Errorf("%s.max_attempts: the value cannot be negative, got %d", option, retry.MaxAttempts))
+	}
+	baseDelay, err := retry.BaseDelayDuration()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("%s.base_delay: %w, expected a value like \"50ms\"", option, err))
+	}
+	maxDelay, err := retry.MaxDelayDuration()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("%s.max_delay: %w, expected a value like \"1s\"", option, err))
+	}
+	if baseDelay > 0 && maxDelay > 0 && baseDelay > maxDelay {
+		errs = append(errs, fmt.Errorf("%s.base_delay: %q is greater than max_delay %q", option, retry.BaseDelay, retry.MaxDelay))
+	}
+	return errs
+}
@@ -0,0 +1,45 @@
+package opts
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	// ScanModePositional scans the selected columns by their positions. It is the default mode.
+	ScanModePositional = "positional"
+	// ScanModeByName scans the selected columns by the column names,
+	// so the loaders tolerate the columns reordered by a migration before the code is regenerated.
+	ScanModeByName = "by_name"
+)
+
+var validScanModes = []string{ScanModePositional, ScanModeByName}
+
+// ScanByName returns true if the rows are scanned by the column names.
+func (o *Options) ScanByName() bool {
+	return o.ScanMode == ScanModeByName
+}
+
+func validateScanMode(o *Options) []error {
+	if o.SelectAllColumns && !o.ScanByName() {
+		return []error{fmt.Errorf("select_all_columns: the option requires the %q scan_mode", ScanModeByName)}
+	}
+	if o.ScanMode == "" {
+		return nil
+	}
+	if !slices.Contains(validScanModes, o.ScanMode) {
+		return []error{
+			fmt.Errorf(
+				"scan_mode: unknown mode %q, expected one of %s%s",
+				o.ScanMode,
+				strings.Join(validScanModes, ", "),
+				didYouMean(o.ScanMode, validScanModes),
+			),
+		}
+	}
+	if o.ScanByName() && o.Driver() != SQLDriverPGXV5 {
+		return []error{fmt.Errorf("scan_mode: the %q mode requires the pgx/v5 sql_package", ScanModeByName)}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package opts
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// DuplicateKeysWarn logs a warning and uses the first row of a key with more than one row. It is the default policy.
+	DuplicateKeysWarn = "warn"
+	// DuplicateKeysError returns the DuplicateKeyError for a key with more than one row.
+	DuplicateKeysError = "error"
+	// DuplicateKeysFirst silently uses the first row of a key with more than one row.
+	// The loader queries are not ordered, so the used row is arbitrary.
+	DuplicateKeysFirst = "first"
+)
+
+var validDuplicateKeys = []string{DuplicateKeysWarn, DuplicateKeysError, DuplicateKeysFirst}
+
+// TableOptions are the runtime settings of the loaders of the matching tables.
+// The zero values fall back to the global options of the same names.
+type TableOptions struct {
+	// Table is the name of the table of a loader in the format [schema.]tablename.
+	Table string `json:"table" yaml:"table"`
+	// MaxKeysPerQuery splits the batches with more keys into several queries. There is no limit if it is 0.
+	MaxKeysPerQuery int `json:"max_keys_per_query,omitempty" yaml:"max_keys_per_query"`
+	// QueryConcurrency is the number of the parallel queries of one split batch.
+	QueryConcurrency int `json:"query_concurrency,omitempty" yaml:"query_concurrency"`
+	// QueryTimeout bounds the context of each query of a batch, e.g. "500ms" or "2s". There is no timeout if it is empty.
+	QueryTimeout string `json:"query_timeout,omitempty" yaml:"query_timeout"`
+	// Retry retries the failed queries of the transient errors. The queries are not retried if it is nil.
+	Retry *Retry `json:"retry,omitempty" yaml:"retry"`
+	// DuplicateKeys is the handling of the keys with more than one row: warn (by default), error or first.
+	DuplicateKeys string `json:"duplicate_keys,omitempty" yaml:"duplicate_keys"`
+
+	TableRef TableRef `json:"-" yaml:"-"`
+}
+
+// FindTableOptions returns the first table options matching the table merged with the global options.
+func (o *Options) FindTableOptions(schema, rel string) TableOptions {
+	result := TableOptions{
+		MaxKeysPerQuery:  o.MaxKeysPerQuery,
+		QueryConcurrency: o.QueryConcurrency,
+		QueryTimeout:     o.QueryTimeout,
+		Retry:            o.Retry,
+		DuplicateKeys:    o.DuplicateKeys,
+	}
+	for _, t := range o.Tables {
+		if !t.TableRef.Matches(schema, rel) {
+			continue
+		}
+		result.Table = t.Table
+		result.TableRef = t.TableRef
+		if t.MaxKeysPerQuery != 0 {
+			result.MaxKeysPerQuery = t.MaxKeysPerQuery
+		}
+		if t.QueryConcurrency != 0 {
+			result.QueryConcurrency = t.QueryConcurrency
+		}
+		if t.QueryTimeout != "" {
+			result.QueryTimeout = t.QueryTimeout
+		}
+		if t.Retry != nil {
+			result.Retry = t.Retry
+		}
+		if t.DuplicateKeys != "" {
+			result.DuplicateKeys = t.DuplicateKeys
+		}
+		break
+	}
+	return result
+}
+
+// QueryTimeoutDuration returns the parsed query timeout.
+func (t TableOptions) QueryTimeoutDuration() (time.Duration, error) {
+	return ParseTTL(t.QueryTimeout)
+}
+
+func validateTableOptions(option string, t TableOptions, tables []catalogTable) []error {
+	var errs []error
+	if t.Table == "" {
+		errs = append(errs, fmt.Errorf("%s.table: missing table name", option))
+	} else if matchTables(t.TableRef, tables) == nil {
+		errs = append(errs, tableNotFound(option+".table", t.Table, tables))
+	}
+	errs = append(errs, validateQueryLimits(option+".", t.MaxKeysPerQuery, t.QueryConcurrency, t.QueryTimeout)...)
+	errs = append(errs, validateRetry(option+".retry", t.Retry)...)
+	errs = append(errs, validateDuplicateKeys(option+".duplicate_keys", t.DuplicateKeys)...)
+	return errs
+}
+
+// validateQueryLimits checks the max_keys_per_query, query_concurrency and query_timeout options with the given prefix.
+func validateQueryLimits(prefix string, maxKeys, concurrency int, timeout string) []error {
+	var errs []error
+	if maxKeys < 0 {
+		errs = append(errs, fmt.Errorf("%smax_keys_per_query: the value cannot be negative, got %d", prefix, maxKeys))
+	}
+	if concurrency < 0 {
+		errs = append(errs, fmt.Errorf("%squery_concurrency: the value cannot be negative, got %d", prefix, concurrency))
+	}
+	if _, err := ParseTTL(timeout); err != nil {
+		errs = append(errs, fmt.Errorf("%squery_timeout: %w, expected a value like \"2s\"", prefix, err))
+	}
+	return errs
+}
+
+func validateDuplicateKeys(option string, policy string) []error {
+	if policy == "" || slices.Contains(validDuplicateKeys, policy) {
+		return nil
+	}
+	return []error{
+		fmt.Errorf(
+			"%s: unknown policy %q, expected one of %s%s",
+			option,
+			policy,
+			strings.Join(validDuplicateKeys, ", "),
+			didYouMean(policy, validDuplicateKeys),
+		),
+	}
+}
@@ -0,0 +1,62 @@
+package opts
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ttlUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"µs": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+	"d":  24 * time.Hour,
+	"w":  7 * 24 * time.Hour,
+}
+
+// ParseTTL parses the time to live of the cached items.
+// It accepts the time.ParseDuration format extended with days ("d") and weeks ("w"),
+// e.g. "1w", "1d3h20m40s", "1.5h". The empty value means that the items never expire.
+func ParseTTL(ttl string) (time.Duration, error) {
+	if ttl == "" || ttl == "0" {
+		return 0, nil
+	}
+	var total time.Duration
+	rest := ttl
+	for rest != "" {
+		numberEnd := strings.IndexFunc(rest, func(r rune) bool {
+			return (r < '0' || r > '9') && r != '.'
+		})
+		if numberEnd <= 0 {
+			return 0, fmt.Errorf("invalid duration %q", ttl)
+		}
+		number, err := strconv.ParseFloat(rest[:numberEnd], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", ttl)
+		}
+		rest = rest[numberEnd:]
+		unitEnd := strings.IndexFunc(rest, func(r rune) bool {
+			return (r >= '0' && r <= '9') || r == '.'
+		})
+		if unitEnd == -1 {
+			unitEnd = len(rest)
+		}
+		unit, ok := ttlUnits[rest[:unitEnd]]
+		if !ok {
+			return 0, fmt.Errorf("unknown unit %q in duration %q", rest[:unitEnd], ttl)
+		}
+		rest = rest[unitEnd:]
+		total += time.Duration(number * float64(unit))
+	}
+	return total, nil
+}
+
+// TtlDuration returns the parsed time to live of the cached items.
+func (c Cache) TtlDuration() (time.Duration, error) {
+	return ParseTTL(c.Ttl)
+}
@@ -0,0 +1,39 @@
+package opts
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTTL(t *testing.T) {
+	for _, test := range []struct {
+		ttl      string
+		expected time.Duration
+	}{
+		{"", 0},
+		{"0", 0},
+		{"1m", time.Minute},
+		{"1h30m", 90 * time.Minute},
+		{"1.5h", 90 * time.Minute},
+		{"1d", 24 * time.Hour},
+		{"1d3h20m40s", 27*time.Hour + 20*time.Minute + 40*time.Second},
+		{"2w", 14 * 24 * time.Hour},
+		{"500ms", 500 * time.Millisecond},
+	} {
+		tt := test
+		t.Run(tt.ttl, func(t *testing.T) {
+			got, err := ParseTTL(tt.ttl)
+			if err != nil {
+				t.Fatalf("ttl parsing failed; %s", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %s; got %s", tt.expected, got)
+			}
+		})
+	}
+	for _, ttl := range []string{"1x", "d", "1", "1d-3h", "h1"} {
+		if _, err := ParseTTL(ttl); err == nil {
+			t.Errorf("expected parse of %q to fail; got nil", ttl)
+		}
+	}
+}
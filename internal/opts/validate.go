@@ -0,0 +1,322 @@
+package opts
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/sqlc-dev/plugin-sdk-go/plugin"
+)
+
+const (
+	CacheTypeMemory  = "memory"
+	CacheTypeLRU     = "lru"
+	CacheTypeNoCache = "no-cache"
+)
+
+var validCacheTypes = []string{CacheTypeMemory, CacheTypeLRU, CacheTypeNoCache}
+
+type catalogTable struct {
+	schema  string
+	rel     string
+	columns []string
+}
+
+func (t catalogTable) fullName() string {
+	return t.schema + "." + t.rel
+}
+
+// ValidateOpts checks the options against the catalog.
+// All found problems are returned as one error.
+// Tables that are skipped because they have no primary key and the include_tables and exclude_tables patterns
+// matching no table are reported as warnings.
+func ValidateOpts(opts *Options, req *plugin.GenerateRequest) error {
+	tables := catalogTables(req, opts.DefaultSchemaName(req))
+
+	var errs []error
+	if opts.SqlPackage != "" {
+		if err := validatePackage(opts.SqlPackage); err != nil {
+			errs = append(errs, fmt.Errorf("sql_package: %w%s", err, didYouMean(opts.SqlPackage, slices.Sorted(maps.Keys(validPackages)))))
+		}
+	}
+	for i, cache := range opts.Cache {
+		errs = append(errs, validateCache(fmt.Sprintf("cache[%d]", i), cache, tables)...)
+	}
+	errs = append(errs, validateQueryLimits("", opts.MaxKeysPerQuery, opts.QueryConcurrency, opts.QueryTimeout)...)
+	errs = append(errs, validateRetry("retry", opts.Retry)...)
+	errs = append(errs, validateDuplicateKeys("duplicate_keys", opts.DuplicateKeys)...)
+	for i, t := range opts.Tables {
+		errs = append(errs, validateTableOptions(fmt.Sprintf("tables[%d]", i), t, tables)...)
+	}
+	errs = append(errs, validateColumnRefs("primary_keys_columns", opts.PrimaryKeysColumns, opts.PrimaryKeyColumnRefs, tables)...)
+	errs = append(errs, validateColumnRefs("include_columns", opts.IncludeColumns, opts.IncludeColumnRefs, tables)...)
+	errs = append(errs, validateColumnRefs("exclude_columns", opts.ExcludeColumns, opts.ExcludeColumnRefs, tables)...)
+	for i, view := range opts.Views {
+		errs = append(errs, validateView(fmt.Sprintf("views[%d]", i), view, tables)...)
+	}
+	for i, fk := range opts.ForeignKeys {
+		errs = append(errs, validateForeignKey(fmt.Sprintf("foreign_keys[%d]", i), fk, tables)...)
+	}
+	for i, m := range opts.ManyToMany {
+		errs = append(errs, validateManyToMany(fmt.Sprintf("many_to_many[%d]", i), m, tables)...)
+	}
+	for i, c := range opts.CountLoaders {
+		errs = append(errs, validateCountLoader(fmt.Sprintf("count_loaders[%d]", i), c, tables)...)
+	}
+	for i, q := range opts.QueryLoaders {
+		errs = append(errs, validateQueryLoader(fmt.Sprintf("query_loaders[%d]", i), q, req.Queries, tables)...)
+	}
+	errs = append(errs, validatePath("templates_dir", opts.TemplatesDir, true)...)
+	errs = append(errs, validatePath("dataloader_template", opts.DataLoaderTemplate, false)...)
+	errs = append(errs, validatePath("loader_factory_template", opts.LoaderFactoryTemplate, false)...)
+	errs = append(errs, validateLayout(opts)...)
+	errs = append(errs, validateScanMode(opts)...)
+	errs = append(errs, validateKeyNormalizers(opts.KeyNormalizers)...)
+	if opts.EmitFakes && opts.ModelImport == "" {
+		errs = append(errs, fmt.Errorf("emit_fakes: the fake loaders require the model_import option"))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid options:\n%w", errors.Join(errs...))
+	}
+
+	// A stale pattern does not break the generation, e.g. after a table is dropped.
+	warnings := unmatchedTableRefs("include_tables", opts.IncludeTables, opts.IncludeTableRefs, tables)
+	warnings = append(warnings, unmatchedTableRefs("exclude_tables", opts.ExcludeTables, opts.ExcludeTableRefs, tables)...)
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "WARNING: %s\n", warning)
+	}
+
+	for _, table := range tables {
+		if !opts.IsTableIncluded(table.schema, table.rel) {
+			continue
+		}
+		if opts.ExcludeViews && opts.IsView(table.schema, table.rel) {
+			continue
+		}
+		if !hasPrimaryKey(opts, table) {
+			fmt.Fprintf(
+				os.Stderr,
+				"WARNING: the loader for %q is skipped, the table has no primary key column. Use the \"primary_keys_columns\" option to configure it.\n",
+				table.fullName(),
+			)
+		}
+	}
+	return nil
+}
+
+func catalogTables(req *plugin.GenerateRequest, defaultSchema string) []catalogTable {
+	var tables []catalogTable
+	if req == nil || req.Catalog == nil {
+		return tables
+	}
+	for _, schema := range req.Catalog.Schemas {
+		if schema.Name == "pg_catalog" || schema.Name == "information_schema" {
+			continue
+		}
+		for _, table := range schema.Tables {
+			t := catalogTable{
+				schema: table.Rel.GetSchema(),
+				rel:    table.Rel.GetName(),
+			}
+			if t.schema == "" {
+				t.schema = defaultSchema
+			}
+			for _, column := range table.Columns {
+				t.columns = append(t.columns, column.Name)
+			}
+			tables = append(tables, t)
+		}
+	}
+	return tables
+}
+
+func hasPrimaryKey(opts *Options, table catalogTable) bool {
+	for _, column := range table.columns {
+		if opts.IsPrimaryKeyColumn(table.schema, table.rel, column) {
+			return true
+		}
+	}
+	return false
+}
+
+func validateCache(option string, cache Cache, tables []catalogTable) []error {
+	var errs []error
+	if cache.Type != "" && !slices.Contains(validCacheTypes, cache.Type) {
+		errs = append(
+			errs,
+			fmt.Errorf(
+				"%s.type: unknown cache type %q, expected one of %s%s",
+				option,
+				cache.Type,
+				strings.Join(validCacheTypes, ", "),
+				didYouMean(cache.Type, validCacheTypes),
+			),
+		)
+	}
+	if cache.Table == "" {
+		errs = append(errs, fmt.Errorf("%s.table: missing table name", option))
+	} else if matchTables(cache.TableRef, tables) == nil {
+		errs = append(errs, tableNotFound(option+".table", cache.Table, tables))
+	}
+	if _, err := cache.TtlDuration(); err != nil {
+		errs = append(errs, fmt.Errorf("%s.ttl: %w, expected a value like \"1d3h20m40s\"", option, err))
+	}
+	if cache.Size < 0 {
+		errs = append(errs, fmt.Errorf("%s.size: the size cannot be negative, got %d", option, cache.Size))
+	}
+	return errs
+}
+
+// unmatchedTableRefs returns the problems of the table patterns matching no table.
+func unmatchedTableRefs(option string, refs []string, parsed []TableRef, tables []catalogTable) []error {
+	var errs []error
+	for i, ref := range parsed {
+		if matchTables(ref, tables) == nil {
+			errs = append(errs, tableNotFound(fmt.Sprintf("%s[%d]", option, i), refs[i], tables))
+		}
+	}
+	return errs
+}
+
+func validateColumnRefs(option string, refs []string, parsed []ColumnRef, tables []catalogTable) []error {
+	var errs []error
+	for i, ref := range parsed {
+		errs = append(errs, validateColumnRef(fmt.Sprintf("%s[%d]", option, i), refs[i], ref, tables)...)
+	}
+	return errs
+}
+
+func validateColumnRef(option string, ref string, parsed ColumnRef, tables []catalogTable) []error {
+	matched := matchTables(parsed.TableRef, tables)
+	if matched == nil {
+		return []error{tableNotFound(option, ref[:strings.LastIndex(ref, ".")], tables)}
+	}
+	var columns []string
+	for _, table := range matched {
+		for _, column := range table.columns {
+			if parsed.Column.MatchString(column) {
+				return nil
+			}
+			columns = append(columns, column)
+		}
+	}
+	column := ref[strings.LastIndex(ref, ".")+1:]
+	return []error{
+		fmt.Errorf("%s: column %q not found in %q%s", option, column, ref, didYouMean(column, columns)),
+	}
+}
+
+func validateView(option string, view View, tables []catalogTable) []error {
+	for _, table := range tables {
+		if table.schema != view.Schema || table.rel != view.Rel {
+			continue
+		}
+		if !slices.Contains(table.columns, view.Key) {
+			return []error{
+				fmt.Errorf(
+					"%s.key: column %q not found in %q%s",
+					option,
+					view.Key,
+					view.Name,
+					didYouMean(view.Key, table.columns),
+				),
+			}
+		}
+		return nil
+	}
+	names := make([]string, 0, len(tables))
+	for _, table := range tables {
+		names = append(names, table.fullName())
+	}
+	return []error{
+		fmt.Errorf("%s.name: view %q not found%s", option, view.Name, didYouMean(view.Schema+"."+view.Rel, names)),
+	}
+}
+
+func validateForeignKey(option string, fk ForeignKey, tables []catalogTable) []error {
+	errs := validateColumnRef(option+".column", fk.Column, fk.ColumnRef, tables)
+	if matchTables(fk.ReferencesRef, tables) == nil {
+		errs = append(errs, tableNotFound(option+".references", fk.References, tables))
+	}
+	return errs
+}
+
+func validatePath(option string, path string, isDir bool) []error {
+	if path == "" {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return []error{fmt.Errorf("%s: %w", option, err)}
+	}
+	if info.IsDir() != isDir {
+		if isDir {
+			return []error{fmt.Errorf("%s: %q is not a directory", option, path)}
+		}
+		return []error{fmt.Errorf("%s: %q is a directory", option, path)}
+	}
+	return nil
+}
+
+func matchTables(ref TableRef, tables []catalogTable) []catalogTable {
+	var matched []catalogTable
+	for _, table := range tables {
+		if ref.Matches(table.schema, table.rel) {
+			matched = append(matched, table)
+		}
+	}
+	return matched
+}
+
+func tableNotFound(option string, table string, tables []catalogTable) error {
+	names := make([]string, 0, len(tables))
+	for _, t := range tables {
+		names = append(names, t.fullName())
+		if !strings.Contains(table, ".") {
+			names = append(names, t.rel)
+		}
+	}
+	return fmt.Errorf("%s: no table matches %q%s", option, table, didYouMean(table, names))
+}
+
+// didYouMean returns a suggestion of the closest candidate to the value
+// or an empty string if there is no close candidate.
+func didYouMean(value string, candidates []string) string {
+	best := ""
+	bestDistance := len(value)/3 + 2
+	for _, candidate := range candidates {
+		d := levenshtein(value, candidate)
+		if d < bestDistance {
+			best = candidate
+			bestDistance = d
+		}
+	}
+	if best == "" || best == value {
+		return ""
+	}
+	return fmt.Sprintf(", did you mean %q?", best)
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
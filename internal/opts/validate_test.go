@@ -0,0 +1,307 @@
+package opts
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/sqlc-dev/plugin-sdk-go/plugin"
+)
+
+func validationRequest(t *testing.T, options map[string]any) *plugin.GenerateRequest {
+	t.Helper()
+	options["package"] = "dataloader"
+	pluginOptions, err := json.Marshal(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tableIdent := &plugin.Identifier{Schema: "public", Name: "authors"}
+	return &plugin.GenerateRequest{
+		PluginOptions: pluginOptions,
+		Queries: []*plugin.Query{
+			{
+				Name: "ListAuthorsByIDs",
+				Columns: []*plugin.Column{
+					{Name: "authors", EmbedTable: tableIdent},
+					{Name: "books_count"},
+				},
+				Params: []*plugin.Parameter{{Number: 1}},
+			},
+		},
+		Catalog: &plugin.Catalog{
+			DefaultSchema: "public",
+			Schemas: []*plugin.Schema{
+				{
+					Name: "public",
+					Tables: []*plugin.Table{
+						{
+							Rel: tableIdent,
+							Columns: []*plugin.Column{
+								{Name: "id", Table: tableIdent},
+								{Name: "name", Table: tableIdent},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestValidateOpts(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		options  map[string]any
+		errs     []string
+		warnings []string
+	}{
+		{
+			name: "valid options",
+			options: map[string]any{
+				"cache":                []map[string]any{{"table": "public.authors", "type": "lru", "ttl": "1d12h", "size": 10}},
+				"primary_keys_columns": []string{"authors.name"},
+				"exclude_columns":      []string{"public.authors.name"},
+				"include_tables":       []string{"public.*"},
+			},
+		},
+		{
+			name: "unknown cache type",
+			options: map[string]any{
+				"cache": []map[string]any{{"table": "public.authors", "type": "lur"}},
+			},
+			errs: []string{`cache[0].type: unknown cache type "lur", expected one of memory, lru, no-cache, did you mean "lru"?`},
+		},
+		{
+			name: "unknown cache table and invalid ttl",
+			options: map[string]any{
+				"cache": []map[string]any{{"table": "public.author", "type": "lru", "ttl": "1x"}},
+			},
+			errs: []string{
+				`cache[0].table: no table matches "public.author", did you mean "public.authors"?`,
+				`cache[0].ttl: unknown unit "x" in duration "1x"`,
+			},
+		},
+		{
+			name: "missing primary key column",
+			options: map[string]any{
+				"primary_keys_columns": []string{"authors.nme"},
+			},
+			errs: []string{`primary_keys_columns[0]: column "nme" not found in "authors.nme", did you mean "name"?`},
+		},
+		{
+			name: "missing excluded and included tables",
+			options: map[string]any{
+				"exclude_tables": []string{"athors"},
+				"include_tables": []string{"public.authors", "public.book*"},
+			},
+			warnings: []string{
+				`WARNING: include_tables[1]: no table matches "public.book*"`,
+				`WARNING: exclude_tables[0]: no table matches "athors", did you mean "authors"?`,
+			},
+		},
+		{
+			name: "missing view",
+			options: map[string]any{
+				"views": []map[string]any{{"name": "authors_mv", "key": "id"}},
+			},
+			errs: []string{`views[0].name: view "authors_mv" not found, did you mean "public.authors"?`},
+		},
+		{
+			name: "unknown sql package",
+			options: map[string]any{
+				"sql_package": "pgx/v6",
+			},
+			errs: []string{`sql_package: unknown SQL package: pgx/v6, did you mean "pgx/v4"?`},
+		},
+		{
+			name: "missing foreign key column and referenced table",
+			options: map[string]any{
+				"foreign_keys": []map[string]any{{"column": "authors.author_id", "references": "author"}},
+			},
+			errs: []string{
+				`foreign_keys[0].column: column "author_id" not found in "authors.author_id"`,
+				`foreign_keys[0].references: no table matches "author", did you mean "authors"?`,
+			},
+		},
+		{
+			name: "unknown output layout",
+			options: map[string]any{
+				"output_layout": "single-file",
+			},
+			errs: []string{`output_layout: unknown layout "single-file", expected one of per_table, single_file, per_schema, did you mean "single_file"?`},
+		},
+		{
+			name: "invalid loader filename",
+			options: map[string]any{
+				"output_layout":   "per_schema",
+				"loader_filename": "loaders/{name}.tmpl",
+			},
+			errs: []string{
+				`loader_filename: the pattern is used only by the "per_table" layout`,
+				`loader_filename: the pattern "loaders/{name}.tmpl" cannot contain a directory`,
+				`loader_filename: the pattern "loaders/{name}.tmpl" must have the .go extension`,
+			},
+		},
+		{
+			name: "negative query limits",
+			options: map[string]any{
+				"max_keys_per_query": -1,
+				"tables":             []map[string]any{{"table": "author", "query_concurrency": -2}},
+			},
+			errs: []string{
+				`max_keys_per_query: the value cannot be negative, got -1`,
+				`tables[0].table: no table matches "author", did you mean "authors"?`,
+				`tables[0].query_concurrency: the value cannot be negative, got -2`,
+			},
+		},
+		{
+			name: "invalid query timeout",
+			options: map[string]any{
+				"tables": []map[string]any{{"table": "authors", "query_timeout": "2sec"}},
+			},
+			errs: []string{`tables[0].query_timeout: unknown unit "sec" in duration "2sec", expected a value like "2s"`},
+		},
+		{
+			name: "unknown duplicate keys policy",
+			options: map[string]any{
+				"tables": []map[string]any{{"table": "authors", "duplicate_keys": "errors"}},
+			},
+			errs: []string{`tables[0].duplicate_keys: unknown policy "errors", expected one of warn, error, first, did you mean "error"?`},
+		},
+		{
+			name: "scan by name without pgx v5",
+			options: map[string]any{
+				"scan_mode":   "by_name",
+				"sql_package": "database/sql",
+			},
+			errs: []string{`scan_mode: the "by_name" mode requires the pgx/v5 sql_package`},
+		},
+		{
+			name: "select all columns without scan by name",
+			options: map[string]any{
+				"select_all_columns": true,
+			},
+			errs: []string{`select_all_columns: the option requires the "by_name" scan_mode`},
+		},
+		{
+			name: "unknown scan mode",
+			options: map[string]any{
+				"scan_mode": "by-name",
+			},
+			errs: []string{`scan_mode: unknown mode "by-name", expected one of positional, by_name, did you mean "by_name"?`},
+		},
+		{
+			name: "invalid key normalizer",
+			options: map[string]any{
+				"key_normalizers": map[string]any{"citext": "github.com/acme/keys.", "bytea": "keys.Trim"},
+			},
+			errs: []string{`key_normalizers.citext: invalid function "github.com/acme/keys.", expected a value like "github.com/acme/keys.Normalize"`},
+		},
+		{
+			name: "missing tables of many to many relation",
+			options: map[string]any{
+				"many_to_many": []map[string]any{
+					{"relation": "authors.id -> author(author_id, tag_id) -> tags.id", "join_columns": []string{"position"}},
+				},
+			},
+			errs: []string{
+				`many_to_many[0].relation: no table matches "author", did you mean "authors"?`,
+				`many_to_many[0].relation: no table matches "tags"`,
+			},
+		},
+		{
+			name: "invalid count loader",
+			options: map[string]any{
+				"count_loaders": []map[string]any{
+					{"column": "authors.author_id", "filter": "status = 'active'; DROP TABLE authors"},
+				},
+			},
+			errs: []string{
+				`count_loaders[0].column: column "author_id" not found in "authors.author_id"`,
+				`count_loaders[0].filter: the filter must be one SQL condition without ";"`,
+			},
+		},
+		{
+			name: "missing query of query loader",
+			options: map[string]any{
+				"query_loaders": []map[string]any{{"query": "ListAuthorByIDs", "key": "id"}},
+			},
+			errs: []string{`query_loaders[0].query: query "ListAuthorByIDs" not found, did you mean "ListAuthorsByIDs"?`},
+		},
+		{
+			name: "missing key of query loader",
+			options: map[string]any{
+				"query_loaders": []map[string]any{{"query": "ListAuthorsByIDs", "key": "author.id"}},
+			},
+			errs: []string{`query_loaders[0].key: column "author.id" not found in the result of "ListAuthorsByIDs", did you mean "authors.id"?`},
+		},
+		{
+			name: "invalid retry",
+			options: map[string]any{
+				"retry":  map[string]any{"max_attempts": -1, "base_delay": "2s", "max_delay": "1s"},
+				"tables": []map[string]any{{"table": "authors", "retry": map[string]any{"max_delay": "1"}}},
+			},
+			errs: []string{
+				`retry.max_attempts: the value cannot be negative, got -1`,
+				`retry.base_delay: "2s" is greater than max_delay "1s"`,
+				`tables[0].retry.max_delay: invalid duration "1", expected a value like "1s"`,
+			},
+		},
+	} {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			req := validationRequest(t, tt.options)
+			options, err := Parse(req)
+			if err != nil {
+				t.Fatalf("options parsing failed; %s", err)
+			}
+			stderr := captureStderr(t, func() {
+				err = ValidateOpts(options, req)
+			})
+			for _, msg := range tt.warnings {
+				if !strings.Contains(stderr, msg) {
+					t.Errorf("expected warnings to contain %q; got %q", msg, stderr)
+				}
+			}
+			if len(tt.errs) == 0 {
+				if err != nil {
+					t.Fatalf("expected no error; got %s", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected validation to fail; got nil")
+			}
+			for _, msg := range tt.errs {
+				if !strings.Contains(err.Error(), msg) {
+					t.Errorf("expected error to contain %q; got %q", msg, err.Error())
+				}
+			}
+		})
+	}
+}
+
+// captureStderr returns the text written to the standard error output by the function.
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = stderr }()
+
+	fn()
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
@@ -0,0 +1,57 @@
+package opts
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaterializedViewSuffix is the suffix of the relations that are recognised
+// as materialized views without explicit configuration.
+const MaterializedViewSuffix = "_mv"
+
+type View struct {
+	// Name is the name of the view in the format [schema.]viewname.
+	Name string `json:"name" yaml:"name"`
+	// Key is the column of the view used to batch the requests.
+	Key string `json:"key" yaml:"key"`
+
+	Schema string `json:"-"`
+	Rel    string `json:"-"`
+}
+
+func (v *View) parse(defaultSchema string) error {
+	parts := strings.Split(v.Name, ".")
+	switch len(parts) {
+	case 1:
+		v.Schema = defaultSchema
+		v.Rel = parts[0]
+	case 2:
+		v.Schema = parts[0]
+		v.Rel = parts[1]
+	default:
+		return fmt.Errorf("view name %q is not the proper format, expected '[schema.]viewname'", v.Name)
+	}
+	if v.Key == "" {
+		return fmt.Errorf("view %q: missing key column", v.Name)
+	}
+	return nil
+}
+
+// FindView returns the configuration of the view with the given name.
+func (o *Options) FindView(schema, rel string) (View, bool) {
+	for _, v := range o.Views {
+		if v.Schema == schema && v.Rel == rel {
+			return v, true
+		}
+	}
+	return View{}, false
+}
+
+// IsView returns true if the relation is a configured view or a materialized view named with the "_mv" suffix.
+// The other views are not recognised, because the sqlc catalog passes them to the plugin as the tables.
+func (o *Options) IsView(schema, rel string) bool {
+	if _, ok := o.FindView(schema, rel); ok {
+		return true
+	}
+	return strings.HasSuffix(rel, MaterializedViewSuffix)
+}
@@ -0,0 +1,125 @@
+package renderer
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/debugger84/sqlc-dataloader/internal/imports"
+	"github.com/debugger84/sqlc-dataloader/internal/model"
+	"github.com/debugger84/sqlc-dataloader/internal/opts"
+	"github.com/iancoleman/strcase"
+	"github.com/sqlc-dev/plugin-sdk-go/plugin"
+)
+
+// CountLoaderTplData is the data of the count_loader.tmpl template.
+// The template renders the loader of the numbers of the rows of one column.
+type CountLoaderTplData struct {
+	// Loader is the count loader the code is rendered for.
+	Loader CountLoader
+	// Package is the name of the package of the generated code.
+	Package string
+	// Imports are the imports of the generated file.
+	Imports []imports.Import
+}
+
+// CountLoader loads the numbers of the rows of a table by the values of a column,
+// e.g. the numbers of the books by the author ids.
+type CountLoader struct {
+	// Name is the name of the loader without the "Loader" suffix, e.g. "BooksCountByAuthorID".
+	Name string
+	// LoaderName is the name of the loader type, e.g. "BooksCountByAuthorIDLoader".
+	LoaderName string
+	// Struct is the counted table, e.g. books.
+	Struct model.Struct
+	// Key is the column the rows are counted by, e.g. books.author_id.
+	Key *model.Field
+	// Filter is the static SQL condition of the counted rows. It is empty if all rows are counted.
+	Filter string
+	RuntimeSettings
+}
+
+// KeyType returns the Go type of the keys, e.g. "pgtype.UUID".
+func (l *CountLoader) KeyType() string {
+	return l.Key.Type().String()
+}
+
+// Query returns the SQL query counting the rows by the keys.
+func (l *CountLoader) Query() string {
+	where := fmt.Sprintf("%s = ANY($1)", l.Key.DBName())
+	if l.Filter != "" {
+		where += fmt.Sprintf(" AND (%s)", l.Filter)
+	}
+	return fmt.Sprintf(
+		"SELECT %s, count(*) FROM %s WHERE %s GROUP BY %s",
+		l.Key.DBName(),
+		l.Struct.EscapedFullTableName(),
+		where,
+		l.Key.DBName(),
+	)
+}
+
+// buildCountLoaders finds the tables and the columns of the configured count loaders.
+func (r *DataLoaderRenderer) buildCountLoaders() ([]CountLoader, error) {
+	loaders := make([]CountLoader, 0, len(r.options.CountLoaders))
+	for i, c := range r.options.CountLoaders {
+		loader, err := r.newCountLoader(c)
+		if err != nil {
+			return nil, fmt.Errorf("count_loaders[%d]: %w", i, err)
+		}
+		loaders = append(loaders, loader)
+	}
+	return loaders, nil
+}
+
+func (r *DataLoaderRenderer) newCountLoader(c opts.CountLoader) (CountLoader, error) {
+	loader := CountLoader{Filter: c.Filter}
+	s, ok := r.findStruct(c.ColumnRef.TableRef)
+	if !ok {
+		return loader, fmt.Errorf("the table %q is not found", c.Table)
+	}
+	loader.Struct = s
+
+	var err error
+	if loader.Key, err = findField(s, c.KeyColumn); err != nil {
+		return loader, err
+	}
+	if newKeyAdapter(loader.Key, r.options) != nil {
+		return loader, fmt.Errorf(
+			"the type %s of %s.%s needs a key adapter, it is not supported by the count loaders",
+			loader.KeyType(),
+			s.RelName(),
+			c.KeyColumn,
+		)
+	}
+
+	loader.Name = c.Name
+	if loader.Name == "" {
+		loader.Name = strcase.ToCamel(s.RelName()) + "CountBy" + loader.Key.Name()
+	}
+	loader.LoaderName = loader.Name + "Loader"
+	loader.RuntimeSettings, err = newRuntimeSettings(r.options.FindTableOptions(s.Schema(), s.RelName()), s.FullTableName())
+	return loader, err
+}
+
+func (r *DataLoaderRenderer) renderCountLoader(
+	tmpl *template.Template,
+	l CountLoader,
+	importer *imports.ImportBuilder,
+) (*plugin.File, error) {
+	importer = importer.AddWithAlias("github.com/debugger84/sqlc-dataloader", "dl")
+	if l.UsesTime() {
+		importer = importer.AddWithoutAlias("time")
+	}
+	importer = importer.
+		Add(l.Key.Type().Import()).
+		ImportContainer(&l.Struct)
+	tctx := CountLoaderTplData{
+		Loader:  l,
+		Package: r.loaderPackage,
+		Imports: importer.Build(),
+	}
+
+	name := strcase.ToSnake(l.Name)
+	filename := r.loaderFilename(l.Struct.Schema(), l.Struct.RelName(), name)
+	return r.renderFile(tmpl, CountLoaderTemplate, filename, l.Struct.QualifiedName(), &tctx)
+}
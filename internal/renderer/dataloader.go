@@ -9,43 +9,190 @@ import (
 	"github.com/debugger84/sqlc-dataloader/internal/opts"
 	"github.com/iancoleman/strcase"
 	"github.com/sqlc-dev/plugin-sdk-go/plugin"
-	"github.com/sqlc-dev/plugin-sdk-go/sdk"
 	"go/format"
 	"strings"
 	"text/template"
+	"time"
 )
 
+// DataLoaderTplData is the data of the dataloader.tmpl template.
+// The template renders the loader of one table.
 type DataLoaderTplData struct {
-	Struct               LoaderStruct
-	Package              string
+	// Struct is the table the loader is rendered for.
+	Struct LoaderStruct
+	// Package is the name of the package of the generated code.
+	Package string
+	// PrimaryKeyColumnName is the name of the key column in the database, e.g. "id".
 	PrimaryKeyColumnName string
-	PrimaryKeyFieldType  string
-	PrimaryKeyFieldName  string
-	Imports              []imports.Import
+	// PrimaryKeyFieldType is the Go type of the key field, e.g. "pgtype.UUID" or "[]byte".
+	PrimaryKeyFieldType string
+	// PrimaryKeyFieldName is the name of the key field of the model, e.g. "ID".
+	PrimaryKeyFieldName string
+	// Imports are the imports of the generated file.
+	Imports []imports.Import
 }
 
+// LoaderFactoryTplData is the data of the loader_factory.tmpl template.
+// The template renders the factory of all generated loaders.
 type LoaderFactoryTplData struct {
-	Structs      []LoaderStruct
-	Package      string
-	Imports      []imports.Import
+	// Structs are the tables the loaders are rendered for.
+	Structs []LoaderStruct
+	// ManyToMany are the many-to-many relations the loaders are rendered for.
+	ManyToMany []ManyToManyLoader
+	// Counts are the count loaders.
+	Counts []CountLoader
+	// Package is the name of the package of the generated code.
+	Package string
+	// Imports are the imports of the generated file.
+	Imports []imports.Import
+	// ModelPackage is the name of the package of the models, e.g. "model".
+	// It is empty if the loaders are generated in the package of the models.
 	ModelPackage string
 }
 
+// FakesTplData is the data of the fakes.tmpl template.
+// The template renders the in-memory fake loaders for tests.
+type FakesTplData struct {
+	// Structs are the tables the fake loaders are rendered for.
+	Structs []LoaderStruct
+	// ManyToMany are the many-to-many relations the fake loaders are rendered for.
+	ManyToMany []ManyToManyLoader
+	// Counts are the count loaders the fake loaders are rendered for.
+	Counts []CountLoader
+	// Package is the name of the package of the fake loaders, e.g. "dataloadertest".
+	Package string
+	// LoaderPackage is the name of the package of the generated loaders, e.g. "dataloader".
+	LoaderPackage string
+	// Imports are the imports of the generated file.
+	Imports []imports.Import
+}
+
 type DataLoaderRenderer struct {
 	structs       []LoaderStruct
 	loaderPackage string
 	importer      *imports.ImportBuilder
+	options       *opts.Options
+	fileTables    FileTables
+	skipped       []SkippedTable
+	// allStructs are all tables including the ones without loaders.
+	allStructs []model.Struct
 }
 
+// LoaderStruct is a table with the loader settings.
+// All methods of model.Struct are available in the templates, e.g. Type, Fields, FullTableName.
 type LoaderStruct struct {
 	model.Struct
+	// LoaderName is the name of the loader type, e.g. "AuthorLoader".
 	LoaderName string
-	Cache      opts.Cache
+	// Cache is the cache configuration of the loader.
+	Cache opts.Cache
+	// CacheTtl is the Go expression of the parsed cache ttl, e.g. "27*time.Hour + 20*time.Minute".
+	CacheTtl string
+	RuntimeSettings
+	// ScanByName scans the selected columns by the column names.
+	ScanByName bool
+	// SelectAllColumns selects the rows by "SELECT *", the columns unknown at the generation time are skipped by the scan.
+	SelectAllColumns bool
+	// KeyAdapter maps the keys that are not comparable or have to be normalised. It is nil for the other keys.
+	KeyAdapter *KeyAdapter
+	// EmitExists generates the Exists and ExistsMany methods checking the keys by ExistsQuery.
+	EmitExists bool
+}
+
+// EncodedKeyType returns the Go type of the keys of the batches and the cache, e.g. "string" for the []byte keys.
+func (s *LoaderStruct) EncodedKeyType() string {
+	if s.KeyAdapter != nil {
+		return s.KeyAdapter.EncodedType
+	}
+	return s.PrimaryKey().Type().String()
 }
 
+// RuntimeSettings are the settings of dl.LoaderSettings rendered by the loader_settings.tmpl template.
+type RuntimeSettings struct {
+	// Options are the runtime settings of the loader, e.g. MaxKeysPerQuery.
+	Options opts.TableOptions
+	// QueryTimeout is the Go expression of the parsed query timeout, e.g. "2*time.Second". It is empty without the timeout.
+	QueryTimeout string
+	// Retry is the retry policy of the loader. It is nil if the queries are not retried.
+	Retry *RetryPolicy
+	// DuplicateKeys is the Go constant of the duplicate keys policy, e.g. "dl.DuplicateKeysError". It is empty for the default policy.
+	DuplicateKeys string
+}
+
+// newRuntimeSettings parses the durations of the table options of the table in the format schema.tablename.
+func newRuntimeSettings(tableOptions opts.TableOptions, table string) (RuntimeSettings, error) {
+	settings := RuntimeSettings{Options: tableOptions}
+	queryTimeout, err := tableOptions.QueryTimeoutDuration()
+	if err != nil {
+		return settings, fmt.Errorf("query timeout of %s: %w", table, err)
+	}
+	if queryTimeout > 0 {
+		settings.QueryTimeout = durationExpr(queryTimeout)
+	}
+	switch tableOptions.DuplicateKeys {
+	case opts.DuplicateKeysError:
+		settings.DuplicateKeys = "dl.DuplicateKeysError"
+	case opts.DuplicateKeysFirst:
+		settings.DuplicateKeys = "dl.DuplicateKeysFirst"
+	}
+	if tableOptions.Retry != nil {
+		if settings.Retry, err = newRetryPolicy(*tableOptions.Retry); err != nil {
+			return settings, fmt.Errorf("retry of %s: %w", table, err)
+		}
+	}
+	return settings, nil
+}
+
+// UsesTime returns true if the rendered settings refer to the time package.
+func (s RuntimeSettings) UsesTime() bool {
+	return s.QueryTimeout != "" || (s.Retry != nil && (s.Retry.BaseDelay != "" || s.Retry.MaxDelay != ""))
+}
+
+// RetryPolicy is the retry policy with the delays as the Go expressions, e.g. "50*time.Millisecond".
+// The empty delays are not set in the generated code.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   string
+	MaxDelay    string
+}
+
+// Query returns the SQL query selecting the rows of the table by the keys.
+// All columns are selected by "*" if the select_all_columns option is enabled.
+// The loaders of the sqlc queries use the query as is.
+func (s *LoaderStruct) Query() string {
+	if s.IsQuery() {
+		return s.QueryText()
+	}
+	columns := s.SqlFieldNamesString()
+	if s.SelectAllColumns {
+		columns = "*"
+	}
+	return fmt.Sprintf(
+		"SELECT %s FROM %s WHERE %s = ANY($1)",
+		columns,
+		s.EscapedFullTableName(),
+		s.PrimaryKey().DBName(),
+	)
+}
+
+// ExistsQuery returns the SQL query selecting the keys of the existing rows by the keys.
+// It is empty without the emit_exists option and for the loaders of the sqlc queries.
+func (s *LoaderStruct) ExistsQuery() string {
+	if !s.EmitExists || s.IsQuery() {
+		return ""
+	}
+	return fmt.Sprintf(
+		"SELECT %s FROM %s WHERE %s = ANY($1)",
+		s.PrimaryKey().DBName(),
+		s.EscapedFullTableName(),
+		s.PrimaryKey().DBName(),
+	)
+}
+
+// SqlFieldNamesString returns the comma separated list of the selected columns.
 func (s *LoaderStruct) SqlFieldNamesString() string {
 	var fields []string
-	for _, f := range s.Fields() {
+	for _, f := range s.SelectedFields() {
 		fields = append(fields, f.DBName())
 	}
 	return strings.Join(fields, ", ")
@@ -55,128 +202,288 @@ func NewDataLoaderRenderer(
 	structs []model.Struct,
 	options *opts.Options,
 	importer *imports.ImportBuilder,
-) *DataLoaderRenderer {
+) (*DataLoaderRenderer, error) {
 	loaderStructs := make([]LoaderStruct, 0, len(structs))
+	var skipped []SkippedTable
 	defCache := opts.Cache{
-		Type: "no-cache",
+		Type: opts.CacheTypeNoCache,
 	}
 	for _, s := range structs {
 		structCache := defCache
 		loaderName := fmt.Sprintf("%sLoader", s.Type().TypeName())
-		for _, cache := range options.Cache {
-			if cache.Table == s.FullTableName() &&
-				(cache.Type == "lru" || cache.Type == "memory") {
-				structCache = cache
-				break
-			}
+		if s.IsQuery() {
+			loaderName = fmt.Sprintf("%sLoader", s.TableName())
 		}
-
-		skip := false
-		for _, table := range options.ExcludeTables {
-			if table == s.FullTableName() {
-				skip = true
-				break
-			}
+		if cache, ok := options.FindCache(s.Schema(), s.RelName()); ok &&
+			(cache.Type == opts.CacheTypeLRU || cache.Type == opts.CacheTypeMemory) {
+			structCache = cache
 		}
-		if skip {
+
+		if reason := skipReason(s, options); reason != "" {
+			skipped = append(skipped, SkippedTable{Table: s.QualifiedName(), Reason: reason})
 			continue
 		}
 
-		loaderStructs = append(
-			loaderStructs, LoaderStruct{
-				Struct:     s,
-				LoaderName: loaderName,
-				Cache:      structCache,
-			},
-		)
+		ttl, err := structCache.TtlDuration()
+		if err != nil {
+			return nil, fmt.Errorf("cache of %s: %w", s.FullTableName(), err)
+		}
+
+		settings, err := newRuntimeSettings(options.FindTableOptions(s.Schema(), s.RelName()), s.FullTableName())
+		if err != nil {
+			return nil, err
+		}
+
+		loaderStruct := LoaderStruct{
+			Struct:           s,
+			LoaderName:       loaderName,
+			Cache:            structCache,
+			CacheTtl:         durationExpr(ttl),
+			RuntimeSettings:  settings,
+			ScanByName:       options.ScanByName() && !s.IsQuery(),
+			SelectAllColumns: options.SelectAllColumns && options.ScanByName() && !s.IsQuery(),
+			KeyAdapter:       newKeyAdapter(s.PrimaryKey(), options),
+			EmitExists:       options.EmitExists,
+		}
+		loaderStructs = append(loaderStructs, loaderStruct)
 	}
 
 	return &DataLoaderRenderer{
 		structs:       loaderStructs,
 		loaderPackage: options.Package,
 		importer:      importer,
+		options:       options,
+		fileTables:    FileTables{},
+		skipped:       skipped,
+		allStructs:    structs,
+	}, nil
+}
+
+// skipReason returns the reason why the loader of the table is not generated
+// or an empty string if it is generated.
+func skipReason(s model.Struct, options *opts.Options) string {
+	switch {
+	case s.IsQuery():
+		return ""
+	case !options.IsTableIncluded(s.Schema(), s.RelName()):
+		return "excluded by the include_tables or exclude_tables option"
+	case s.IsView() && options.ExcludeViews:
+		return "excluded by the exclude_views option"
+	case !s.HasPrimaryKey():
+		return "no primary key column"
+	}
+	return ""
+}
+
+// newRetryPolicy parses the delays of the retry option. The zero delays are left empty to use the runtime defaults.
+func newRetryPolicy(retry opts.Retry) (*RetryPolicy, error) {
+	baseDelay, err := retry.BaseDelayDuration()
+	if err != nil {
+		return nil, err
+	}
+	maxDelay, err := retry.MaxDelayDuration()
+	if err != nil {
+		return nil, err
+	}
+	policy := &RetryPolicy{MaxAttempts: retry.MaxAttempts}
+	if baseDelay > 0 {
+		policy.BaseDelay = durationExpr(baseDelay)
+	}
+	if maxDelay > 0 {
+		policy.MaxDelay = durationExpr(maxDelay)
 	}
+	return policy, nil
+}
+
+// durationExpr formats the duration as a Go expression, e.g. "27*time.Hour + 20*time.Minute".
+func durationExpr(d time.Duration) string {
+	if d == 0 {
+		return "0"
+	}
+	units := []struct {
+		unit time.Duration
+		name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+		{time.Microsecond, "time.Microsecond"},
+		{time.Nanosecond, "time.Nanosecond"},
+	}
+	var parts []string
+	for _, u := range units {
+		if n := d / u.unit; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d*%s", n, u.name))
+			d -= n * u.unit
+		}
+	}
+	return strings.Join(parts, " + ")
 }
 
 func (r *DataLoaderRenderer) Render() ([]*plugin.File, error) {
 	if len(r.structs) == 0 {
 		return nil, nil
 	}
-	funcMap := template.FuncMap{
-		"lowerTitle": sdk.LowerTitle,
-	}
-	tmpl := template.Must(
-		template.New("dataloader.tmpl").
-			Funcs(funcMap).
-			ParseFS(
-				templates,
-				"templates/dataloader.tmpl",
-				"templates/loader_factory.tmpl",
-			),
-	)
-	files := make([]*plugin.File, 0)
+	tmpl, err := parseTemplates(r.options)
+	if err != nil {
+		return nil, err
+	}
 	loaderImporter := r.importer.
 		AddWithoutAlias("context").
 		AddWithoutAlias("github.com/graph-gophers/dataloader/v7")
 
+	relations, err := r.buildManyToManyLoaders()
+	if err != nil {
+		return nil, err
+	}
+	counts, err := r.buildCountLoaders()
+	if err != nil {
+		return nil, err
+	}
+
+	loaderFiles := make([]*plugin.File, 0, len(r.structs)+len(relations)+len(counts))
+	schemas := make([]string, 0, len(loaderFiles))
 	for _, s := range r.structs {
-		if !s.HasPrimaryKey() {
-			continue
-		}
 		file, err := r.renderDataLoader(tmpl, s, loaderImporter)
 		if err != nil {
 			return nil, err
 		}
-		files = append(files, file)
+		loaderFiles = append(loaderFiles, file)
+		schemas = append(schemas, s.Schema())
+	}
+	for _, l := range relations {
+		file, err := r.renderManyToManyLoader(tmpl, l, loaderImporter)
+		if err != nil {
+			return nil, err
+		}
+		loaderFiles = append(loaderFiles, file)
+		schemas = append(schemas, l.Join.Schema())
+	}
+	for _, l := range counts {
+		file, err := r.renderCountLoader(tmpl, l, loaderImporter)
+		if err != nil {
+			return nil, err
+		}
+		loaderFiles = append(loaderFiles, file)
+		schemas = append(schemas, l.Struct.Schema())
 	}
 
 	factoryImporter := r.importer
-	file, err := r.renderLoaderFactory(tmpl, r.structs, factoryImporter)
+	factoryFile, err := r.renderLoaderFactory(tmpl, r.structs, relations, counts, factoryImporter)
 	if err != nil {
 		return nil, err
 	}
-	files = append(files, file)
+
+	files, err := r.layoutFiles(loaderFiles, schemas, factoryFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.options.EmitFakes {
+		file, err := r.renderFakes(tmpl, r.structs, relations, counts, r.importer)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	if r.options.Gqlgen {
+		file, err := r.renderGqlgen(tmpl, r.structs, r.importer)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	if r.options.EmitManifest {
+		file, err := r.renderManifest()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
 
 	return files, nil
 }
 
+// layoutFiles arranges the rendered loaders and the loader factory by the output_layout option.
+// The schemas are the schemas of the loader files.
+func (r *DataLoaderRenderer) layoutFiles(
+	loaderFiles []*plugin.File,
+	schemas []string,
+	factoryFile *plugin.File,
+) ([]*plugin.File, error) {
+	switch r.options.Layout() {
+	case opts.OutputLayoutSingleFile:
+		file, err := r.mergeFiles(r.loaderDir()+opts.SingleFileName, append(loaderFiles, factoryFile))
+		if err != nil {
+			return nil, err
+		}
+		return []*plugin.File{file}, nil
+	case opts.OutputLayoutPerSchema:
+		var fileSchemas []string
+		schemaFiles := map[string][]*plugin.File{}
+		for i, schema := range schemas {
+			if _, ok := schemaFiles[schema]; !ok {
+				fileSchemas = append(fileSchemas, schema)
+			}
+			schemaFiles[schema] = append(schemaFiles[schema], loaderFiles[i])
+		}
+		files := make([]*plugin.File, 0, len(fileSchemas)+1)
+		for _, schema := range fileSchemas {
+			file, err := r.mergeFiles(r.loaderDir()+opts.SchemaFilename(schema), schemaFiles[schema])
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, file)
+		}
+		return append(files, factoryFile), nil
+	default:
+		return append(loaderFiles, factoryFile), nil
+	}
+}
+
+// loaderDir returns the directory of the loaders relative to the output directory with the trailing slash.
+// It is empty if the loaders are generated in the package of the models.
+func (r *DataLoaderRenderer) loaderDir() string {
+	if len(r.structs) == 0 || r.loaderPackage == r.structs[0].Type().PackageName() {
+		return ""
+	}
+	return r.loaderPackage + "/"
+}
+
+// loaderFilename returns the path of the file of the loader in the per_table layout.
+func (r *DataLoaderRenderer) loaderFilename(schema, rel, name string) string {
+	dir := r.loaderDir()
+	return dir + r.options.LoaderFilename(schema, rel, name, dir == "")
+}
+
 func (r *DataLoaderRenderer) renderLoaderFactory(
 	tmpl *template.Template,
 	structs []LoaderStruct,
+	relations []ManyToManyLoader,
+	counts []CountLoader,
 	importer *imports.ImportBuilder,
 ) (*plugin.File, error) {
 	s := structs[0]
+	importer = importer.
+		AddWithAlias("github.com/debugger84/sqlc-dataloader", "dl").
+		ImportContainer(&s)
+	for _, ls := range structs {
+		importer = importer.Add(ls.PrimaryKey().Type().Import())
+	}
 	tctx := LoaderFactoryTplData{
 		Structs:      structs,
+		ManyToMany:   relations,
+		Counts:       counts,
 		Package:      r.loaderPackage,
 		ModelPackage: s.Type().PackageName(),
-		Imports: importer.
-			ImportContainer(&s).
-			Build(),
+		Imports:      importer.Build(),
 	}
 
-	var b bytes.Buffer
-	w := bufio.NewWriter(&b)
-	err := tmpl.ExecuteTemplate(w, "loader_factory.tmpl", &tctx)
-	w.Flush()
-	if err != nil {
-		return nil, err
-	}
-	code, err := format.Source(b.Bytes())
-	if err != nil {
-		fmt.Println(b.String())
-		return nil, fmt.Errorf("source error: %w", err)
-	}
-	filename := fmt.Sprintf("loader_factory.go")
-	if r.loaderPackage != s.Type().PackageName() {
-		filename = fmt.Sprintf("%s/%s", r.loaderPackage, filename)
-	}
-
-	file := &plugin.File{
-		Name:     filename,
-		Contents: code,
-	}
-	return file, nil
+	filename := r.loaderDir() + "loader_factory.go"
+	return r.renderFile(tmpl, LoaderFactoryTemplate, filename, "", &tctx)
 }
 
 func (r *DataLoaderRenderer) renderDataLoader(
@@ -184,27 +491,32 @@ func (r *DataLoaderRenderer) renderDataLoader(
 	s LoaderStruct,
 	importer *imports.ImportBuilder,
 ) (*plugin.File, error) {
-	var pkField model.Field
-	for _, f := range s.Fields() {
-		if f.IsPrimaryKey() {
-			pkField = f
-			break
-		}
-	}
+	pkField := s.PrimaryKey()
 
-	if s.Cache.Type == "lru" {
+	if s.Cache.Type == opts.CacheTypeLRU {
 		importer = importer.
 			AddWithAlias("github.com/debugger84/sqlc-dataloader/cache", "loaderCache").
 			AddWithoutAlias("time")
 	}
+	if s.UsesTime() {
+		importer = importer.AddWithoutAlias("time")
+	}
 
 	importer = importer.AddWithAlias("github.com/debugger84/sqlc-dataloader", "dl")
+	if s.KeyAdapter != nil {
+		importer = importer.Add(s.KeyAdapter.Import)
+	}
+	if s.ScanByName {
+		importer = importer.
+			AddWithoutAlias("fmt").
+			AddWithoutAlias(string(opts.SQLDriverPGXV5))
+	}
 
 	tctx := DataLoaderTplData{
 		Struct:               s,
 		Package:              r.loaderPackage,
 		PrimaryKeyColumnName: pkField.DBName(),
-		PrimaryKeyFieldType:  pkField.Type().TypeWithPackage(),
+		PrimaryKeyFieldType:  pkField.Type().String(),
 		PrimaryKeyFieldName:  pkField.Name(),
 		Imports: importer.
 			Add(pkField.Type().Import()).
@@ -212,25 +524,82 @@ func (r *DataLoaderRenderer) renderDataLoader(
 			Build(),
 	}
 
+	name := strcase.ToSnake(strings.TrimSuffix(s.LoaderName, "Loader"))
+	filename := r.loaderFilename(s.Schema(), s.RelName(), name)
+	return r.renderFile(tmpl, DataLoaderTemplate, filename, s.QualifiedName(), &tctx)
+}
+
+func (r *DataLoaderRenderer) renderFakes(
+	tmpl *template.Template,
+	structs []LoaderStruct,
+	relations []ManyToManyLoader,
+	counts []CountLoader,
+	importer *imports.ImportBuilder,
+) (*plugin.File, error) {
+	s := structs[0]
+	importer = importer.
+		AddWithoutAlias("context").
+		AddWithoutAlias("sync").
+		AddWithoutAlias(r.options.LoaderImport()).
+		AddWithAlias("github.com/debugger84/sqlc-dataloader", "dl").
+		ImportContainer(&s)
+	for _, ls := range structs {
+		importer = importer.Add(ls.PrimaryKey().Type().Import())
+	}
+	for _, l := range relations {
+		importer = importer.Add(l.Key.Type().Import())
+	}
+	for _, l := range counts {
+		importer = importer.Add(l.Key.Type().Import())
+	}
+	tctx := FakesTplData{
+		Structs:       structs,
+		ManyToMany:    relations,
+		Counts:        counts,
+		Package:       r.options.FakesPackage,
+		LoaderPackage: r.loaderPackage,
+		Imports:       importer.Build(),
+	}
+
+	filename := fmt.Sprintf("%s%s/fakes.go", r.loaderDir(), r.options.FakesPackage)
+	return r.renderFile(tmpl, FakesTemplate, filename, "", &tctx)
+}
+
+// renderFile executes the template and formats the result.
+// The table is the full name of the table the file is rendered for, it is empty for the shared files.
+func (r *DataLoaderRenderer) renderFile(
+	tmpl *template.Template,
+	name string,
+	filename string,
+	table string,
+	data any,
+) (*plugin.File, error) {
 	var b bytes.Buffer
 	w := bufio.NewWriter(&b)
-	err := tmpl.ExecuteTemplate(w, "dataloader.tmpl", &tctx)
+	err := tmpl.ExecuteTemplate(w, name, data)
 	w.Flush()
 	if err != nil {
 		return nil, err
 	}
 	code, err := format.Source(b.Bytes())
 	if err != nil {
-		fmt.Println(b.String())
-		return nil, fmt.Errorf("source error: %w", err)
+		if table != "" {
+			return nil, fmt.Errorf("source error in %s (table %s): %w", filename, table, err)
+		}
+		return nil, fmt.Errorf("source error in %s: %w", filename, err)
 	}
-	filename := fmt.Sprintf("%s_loader.go", strcase.ToSnake(s.Type().TypeName()))
-	if r.loaderPackage != s.Type().PackageName() {
-		filename = fmt.Sprintf("%s/%s.go", r.loaderPackage, strcase.ToSnake(s.Type().TypeName()))
+	if table != "" {
+		r.fileTables[filename] = []TableLines{{Table: table}}
 	}
-	file := &plugin.File{
+
+	return &plugin.File{
 		Name:     filename,
 		Contents: code,
-	}
-	return file, nil
+	}, nil
+}
+
+// FileTables returns the tables the rendered files are generated for by the file names.
+// The shared files like the loader factory are not listed.
+func (r *DataLoaderRenderer) FileTables() FileTables {
+	return r.fileTables
 }
@@ -0,0 +1,154 @@
+package renderer
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/debugger84/sqlc-dataloader/internal/gotype"
+	"github.com/debugger84/sqlc-dataloader/internal/imports"
+	"github.com/debugger84/sqlc-dataloader/internal/model"
+	"github.com/debugger84/sqlc-dataloader/internal/opts"
+	"github.com/iancoleman/strcase"
+	"github.com/sqlc-dev/plugin-sdk-go/plugin"
+)
+
+const gqlgenImport = "github.com/99designs/gqlgen/graphql"
+
+// GqlgenTplData is the data of the gqlgen.tmpl template.
+// The template renders the gqlgen middleware and the resolver helpers of the foreign keys.
+type GqlgenTplData struct {
+	// Resolvers are the resolver helpers of the foreign keys.
+	Resolvers []ForeignKeyResolver
+	// Package is the name of the package of the generated code.
+	Package string
+	// ModelPackage is the name of the package of the models, e.g. "model".
+	// It is empty if the loaders are generated in the package of the models.
+	ModelPackage string
+	// Imports are the imports of the generated file.
+	Imports []imports.Import
+}
+
+// ForeignKeyResolver is a resolver helper loading the row referenced by a foreign key column.
+type ForeignKeyResolver struct {
+	// Name is the name of the helper, e.g. "ResolveBookAuthor".
+	Name string
+	// Struct is the table with the foreign key column, e.g. books.
+	Struct model.Struct
+	// Field is the foreign key field, e.g. AuthorID.
+	Field *model.Field
+	// Target is the loader of the referenced table, e.g. authors.
+	Target LoaderStruct
+	// NullCheck is the condition that is true if the foreign key is null, e.g. "!obj.AuthorID.Valid".
+	// It is empty if the column is not nullable.
+	NullCheck string
+	// KeyExpr is the expression of the key passed to the loader, e.g. "obj.AuthorID".
+	KeyExpr string
+}
+
+// buildResolvers finds the resolver helpers of the configured foreign keys.
+func (r *DataLoaderRenderer) buildResolvers() ([]ForeignKeyResolver, error) {
+	var resolvers []ForeignKeyResolver
+	for i, fk := range r.options.ForeignKeys {
+		option := fmt.Sprintf("foreign_keys[%d]", i)
+		target, ok := r.findLoaderStruct(fk.ReferencesRef)
+		if !ok {
+			return nil, fmt.Errorf("%s.references: the loader of %q is not generated", option, fk.References)
+		}
+		for _, s := range r.allStructs {
+			if !fk.ColumnRef.MatchesTable(s.Schema(), s.RelName()) {
+				continue
+			}
+			fields := s.Fields()
+			for j := range fields {
+				field := &fields[j]
+				if !fk.ColumnRef.Column.MatchString(field.DBName()) {
+					continue
+				}
+				resolver, err := newForeignKeyResolver(fk, s, field, target)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", option, err)
+				}
+				resolvers = append(resolvers, resolver)
+			}
+		}
+	}
+	return resolvers, nil
+}
+
+func (r *DataLoaderRenderer) findLoaderStruct(ref opts.TableRef) (LoaderStruct, bool) {
+	for _, s := range r.structs {
+		if ref.Matches(s.Schema(), s.RelName()) {
+			return s, true
+		}
+	}
+	return LoaderStruct{}, false
+}
+
+func newForeignKeyResolver(
+	fk opts.ForeignKey,
+	s model.Struct,
+	field *model.Field,
+	target LoaderStruct,
+) (ForeignKeyResolver, error) {
+	name := fk.Name
+	if name == "" {
+		name = target.Type().TypeName()
+		if column := strings.TrimSuffix(field.DBName(), "_id"); column != field.DBName() {
+			name = strcase.ToCamel(column)
+		}
+	}
+	resolver := ForeignKeyResolver{
+		Name:    "Resolve" + s.Type().TypeName() + name,
+		Struct:  s,
+		Field:   field,
+		Target:  target,
+		KeyExpr: "obj." + field.Name(),
+	}
+
+	keyType := target.PrimaryKey().Type().String()
+	fieldType := field.Type().String()
+	switch {
+	case fieldType == "*"+keyType:
+		resolver.NullCheck = "obj." + field.Name() + " == nil"
+		resolver.KeyExpr = "*obj." + field.Name()
+	case fieldType != keyType:
+		return resolver, fmt.Errorf(
+			"the type %s of %s.%s does not match the key type %s of %s",
+			fieldType,
+			s.RelName(),
+			field.DBName(),
+			keyType,
+			target.RelName(),
+		)
+	case !field.Column().GetNotNull() && !field.Type().IsPointer() &&
+		gotype.HasValidField(field.Type().Import().Path, field.Type().TypeName()):
+		resolver.NullCheck = "!obj." + field.Name() + ".Valid"
+	}
+	return resolver, nil
+}
+
+func (r *DataLoaderRenderer) renderGqlgen(
+	tmpl *template.Template,
+	structs []LoaderStruct,
+	importer *imports.ImportBuilder,
+) (*plugin.File, error) {
+	resolvers, err := r.buildResolvers()
+	if err != nil {
+		return nil, err
+	}
+	s := structs[0]
+	tctx := GqlgenTplData{
+		Resolvers:    resolvers,
+		Package:      r.loaderPackage,
+		ModelPackage: s.Type().PackageName(),
+		Imports: importer.
+			AddWithoutAlias("context").
+			AddWithoutAlias("errors").
+			AddWithoutAlias(gqlgenImport).
+			AddWithAlias("github.com/debugger84/sqlc-dataloader", "dl").
+			ImportContainer(&s).
+			Build(),
+	}
+	return r.renderFile(tmpl, GqlgenTemplate, r.loaderDir()+"gqlgen.go", "", &tctx)
+}
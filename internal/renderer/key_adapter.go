@@ -0,0 +1,125 @@
+package renderer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/debugger84/sqlc-dataloader/internal/imports"
+	"github.com/debugger84/sqlc-dataloader/internal/model"
+	"github.com/debugger84/sqlc-dataloader/internal/opts"
+	"github.com/sqlc-dev/plugin-sdk-go/sdk"
+)
+
+// KeyAdapter maps the keys of a loader to the comparable keys of the batches and the cache.
+type KeyAdapter struct {
+	// Expr is the Go expression of the dl.KeyAdapter, e.g. "dl.BytesKeyAdapter[[]byte]()".
+	Expr string
+	// EncodedType is the Go type of the encoded keys, e.g. "string".
+	EncodedType string
+	// Import is the import of the custom normalisation function. It is empty for the built-in adapters.
+	Import imports.Import
+}
+
+// numericKeyAdapter encodes pgtype.Numeric, which holds *big.Int, as the decimal text without the trailing zeros,
+// so the keys 1.5 and 1.50 are the same. The second argument is the check of NULL,
+// the third one is the field marking the decoded value as not NULL.
+const numericKeyAdapter = `dl.KeyAdapter[%[1]s, string]{
+	Encode: func(key %[1]s) string {
+		switch {
+		case %[2]s:
+			return ""
+		case key.NaN:
+			return "NaN"
+		case key.InfinityModifier == pgtype.Infinity:
+			return "Infinity"
+		case key.InfinityModifier == pgtype.NegativeInfinity:
+			return "-Infinity"
+		}
+		return dl.DecimalKey(key.Int, key.Exp)
+	},
+	Decode: func(key string) %[1]s {
+		switch key {
+		case "":
+			return %[1]s{}
+		case "NaN":
+			return %[1]s{NaN: true, %[3]s}
+		case "Infinity":
+			return %[1]s{InfinityModifier: pgtype.Infinity, %[3]s}
+		case "-Infinity":
+			return %[1]s{InfinityModifier: pgtype.NegativeInfinity, %[3]s}
+		}
+		value, exp := dl.ParseDecimalKey(key)
+		return %[1]s{Int: value, Exp: exp, %[3]s}
+	},
+}`
+
+// timestamptzNormalizer normalises the time of pgtype.Timestamptz.
+const timestamptzNormalizer = `func(key %[1]s) %[1]s {
+	key.Time = dl.NormalizeTime(key.Time)
+	return key
+}`
+
+// newKeyAdapter returns the adapter of the key field
+// or nil if the keys are comparable and are not normalised.
+// The custom normaliser of the key_normalizers option replaces the built-in normalisation.
+func newKeyAdapter(field *model.Field, options *opts.Options) *KeyAdapter {
+	keyType := field.Type().String()
+	dbType := ""
+	if field.Column() != nil {
+		dbType = strings.TrimPrefix(sdk.DataType(field.Column().Type), "pg_catalog.")
+	}
+	normalizer, normalizerImport := keyNormalizer(options.KeyNormalizer(dbType))
+
+	var encoder string
+	switch {
+	case keyType == "[]byte" || keyType == "json.RawMessage":
+		encoder = fmt.Sprintf("dl.BytesKeyAdapter[%s]()", keyType)
+	case keyType == "pgtype.Numeric" && options.Driver() == opts.SQLDriverPGXV4:
+		encoder = fmt.Sprintf(numericKeyAdapter, keyType, "key.Status != pgtype.Present", "Status: pgtype.Present")
+	case keyType == "pgtype.Numeric":
+		encoder = fmt.Sprintf(numericKeyAdapter, keyType, "!key.Valid", "Valid: true")
+	}
+	if encoder != "" {
+		adapter := &KeyAdapter{Expr: encoder, EncodedType: "string", Import: normalizerImport}
+		if normalizer != "" {
+			adapter.Expr += fmt.Sprintf(".Normalized(%s)", normalizer)
+		}
+		return adapter
+	}
+
+	if normalizer == "" {
+		switch {
+		case dbType == "citext" && keyType == "string":
+			normalizer = "dl.LowerKey"
+		case dbType == "timestamptz" && keyType == "time.Time":
+			normalizer = "dl.NormalizeTime"
+		case dbType == "timestamptz" && keyType == "pgtype.Timestamptz":
+			normalizer = fmt.Sprintf(timestamptzNormalizer, keyType)
+		case (dbType == "numeric" || dbType == "decimal") && keyType == "string":
+			normalizer = "dl.NormalizeDecimal"
+		default:
+			return nil
+		}
+	}
+	return &KeyAdapter{
+		Expr:        fmt.Sprintf("dl.NormalizedKeyAdapter(%s)", normalizer),
+		EncodedType: keyType,
+		Import:      normalizerImport,
+	}
+}
+
+// keyNormalizer returns the Go expression and the import of the function in the format [import/path.]Func.
+// The package is imported with the alias if its name differs from the last element of the path,
+// e.g. decimal "github.com/acme/go-decimal".
+func keyNormalizer(fn string) (string, imports.Import) {
+	dot := strings.LastIndex(fn, ".")
+	if dot < 0 {
+		return fn, imports.Import{}
+	}
+	imp := imports.Import{Path: fn[:dot]}
+	name := imports.PackageName(imp.Path)
+	if name != imp.Path[strings.LastIndex(imp.Path, "/")+1:] {
+		imp.Alias = name
+	}
+	return name + fn[dot:], imp
+}
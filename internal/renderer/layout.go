@@ -0,0 +1,155 @@
+package renderer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"strings"
+
+	"github.com/sqlc-dev/plugin-sdk-go/plugin"
+)
+
+// TableLines is the table the lines of a generated file starting from FromLine are rendered for.
+// The table is empty for the shared code like the loader factory.
+type TableLines struct {
+	FromLine int
+	Table    string
+}
+
+// FileTables are the tables the generated files are rendered for by the file names.
+type FileTables map[string][]TableLines
+
+// Table returns the full name of the table the line of the file is rendered for.
+func (t FileTables) Table(filename string, line int) (string, bool) {
+	table := ""
+	for _, lines := range t[filename] {
+		if lines.FromLine > line {
+			break
+		}
+		table = lines.Table
+	}
+	return table, table != ""
+}
+
+// mergeFiles merges the rendered files of one package into one file.
+// The merged files are removed from the file tables, the merged file is added instead.
+func (r *DataLoaderRenderer) mergeFiles(filename string, files []*plugin.File) (*plugin.File, error) {
+	sources := make([][]byte, 0, len(files))
+	for _, file := range files {
+		sources = append(sources, file.Contents)
+	}
+	code, firstLines, err := mergeSources(sources)
+	if err != nil {
+		return nil, fmt.Errorf("merge error in %s: %w", filename, err)
+	}
+
+	tables := make([]TableLines, 0, len(files))
+	for i, file := range files {
+		table, _ := r.fileTables.Table(file.Name, 0)
+		tables = append(tables, TableLines{FromLine: firstLines[i], Table: table})
+		delete(r.fileTables, file.Name)
+	}
+	r.fileTables[filename] = tables
+
+	return &plugin.File{
+		Name:     filename,
+		Contents: code,
+	}, nil
+}
+
+// mergeSources merges the Go files of one package into one file.
+// The imports are deduplicated, the declarations keep the order of the files.
+// The returned lines are the first lines of the declarations of each file in the merged code.
+func mergeSources(sources [][]byte) ([]byte, []int, error) {
+	fset := token.NewFileSet()
+	pkgName := ""
+	var imports []string
+	seenImports := map[string]struct{}{}
+	bodies := make([]string, 0, len(sources))
+	declCounts := make([]int, 0, len(sources))
+	for i, src := range sources {
+		file, err := parser.ParseFile(fset, fmt.Sprintf("source%d.go", i), src, parser.ParseComments)
+		if err != nil {
+			return nil, nil, err
+		}
+		if pkgName == "" {
+			pkgName = file.Name.Name
+		} else if pkgName != file.Name.Name {
+			return nil, nil, fmt.Errorf("the files have different packages %q and %q", pkgName, file.Name.Name)
+		}
+
+		bodyStart := file.Name.End()
+		count := 0
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.IMPORT {
+				count++
+				continue
+			}
+			for _, spec := range gen.Specs {
+				importSpec := spec.(*ast.ImportSpec)
+				line := importSpec.Path.Value
+				if importSpec.Name != nil {
+					line = importSpec.Name.Name + " " + line
+				}
+				if _, ok := seenImports[line]; !ok {
+					seenImports[line] = struct{}{}
+					imports = append(imports, line)
+				}
+			}
+			bodyStart = gen.End()
+		}
+		bodies = append(bodies, string(src[fset.Position(bodyStart).Offset:]))
+		declCounts = append(declCounts, count)
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("package %s\n\n", pkgName))
+	if len(imports) > 0 {
+		b.WriteString(fmt.Sprintf("import (\n\t%s\n)\n", strings.Join(imports, "\n\t")))
+	}
+	b.WriteString(strings.Join(bodies, "\n"))
+	code, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	merged, err := parser.ParseFile(fset, "merged.go", code, parser.ParseComments)
+	if err != nil {
+		return nil, nil, err
+	}
+	var decls []ast.Decl
+	for _, decl := range merged.Decls {
+		if gen, ok := decl.(*ast.GenDecl); !ok || gen.Tok != token.IMPORT {
+			decls = append(decls, decl)
+		}
+	}
+	firstLines := make([]int, 0, len(sources))
+	next := 0
+	for _, count := range declCounts {
+		line := fset.Position(merged.End()).Line
+		if next < len(decls) {
+			line = fset.Position(declStart(decls[next])).Line
+		}
+		firstLines = append(firstLines, line)
+		next += count
+	}
+	return code, firstLines, nil
+}
+
+// declStart returns the position of the declaration including its doc comment.
+func declStart(decl ast.Decl) token.Pos {
+	switch d := decl.(type) {
+	case *ast.GenDecl:
+		if d.Doc != nil {
+			return d.Doc.Pos()
+		}
+	case *ast.FuncDecl:
+		if d.Doc != nil {
+			return d.Doc.Pos()
+		}
+	}
+	return decl.Pos()
+}
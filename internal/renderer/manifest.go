@@ -0,0 +1,128 @@
+package renderer
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/debugger84/sqlc-dataloader/internal/opts"
+	"github.com/sqlc-dev/plugin-sdk-go/plugin"
+)
+
+const ManifestFilename = "dataloader_manifest.json"
+
+// Manifest describes the generated loaders for the tooling.
+// It is written to the dataloader_manifest.json file next to the generated code.
+type Manifest struct {
+	// Package is the name of the package of the generated loaders.
+	Package string `json:"package"`
+	// Import is the import path of the package of the generated loaders. It is empty without the model_import option.
+	Import  string           `json:"import,omitempty"`
+	Loaders []ManifestLoader `json:"loaders"`
+	Skipped []SkippedTable   `json:"skipped"`
+}
+
+type ManifestLoader struct {
+	// Name is the name of the loader type, e.g. "AuthorLoader".
+	Name string `json:"name"`
+	// Table is the full name of the table, e.g. "public.authors".
+	Table string `json:"table"`
+	// File is the generated file with the loader relative to the output directory.
+	File string `json:"file"`
+	// Model is the Go type of the loaded items, e.g. "model.Author".
+	Model string        `json:"model"`
+	Keys  []ManifestKey `json:"keys"`
+	// SQL is the query selecting the items by the keys.
+	SQL   string        `json:"sql"`
+	Cache ManifestCache `json:"cache"`
+}
+
+type ManifestKey struct {
+	// Column is the name of the key column in the database, e.g. "id".
+	Column string `json:"column"`
+	// Field is the name of the key field of the model, e.g. "ID".
+	Field string `json:"field"`
+	// GoType is the Go type of the key, e.g. "pgtype.UUID".
+	GoType string `json:"go_type"`
+	// GoImport is the import path of the Go type of the key, e.g. "github.com/jackc/pgx/v5/pgtype".
+	GoImport string `json:"go_import,omitempty"`
+}
+
+type ManifestCache struct {
+	// Type is the cache type: memory, lru or no-cache.
+	Type string `json:"type"`
+	// Ttl is the configured time to live of the lru cache, e.g. "1d".
+	Ttl string `json:"ttl,omitempty"`
+	// TtlSeconds is the parsed time to live of the lru cache.
+	TtlSeconds float64 `json:"ttl_seconds,omitempty"`
+	// Size is the size of the lru cache.
+	Size int `json:"size,omitempty"`
+}
+
+// SkippedTable is a table the loader is not generated for.
+type SkippedTable struct {
+	Table  string `json:"table"`
+	Reason string `json:"reason"`
+}
+
+// renderManifest renders the manifest of the loaders. It must be called after the loaders are rendered.
+func (r *DataLoaderRenderer) renderManifest() (*plugin.File, error) {
+	tableFiles := map[string]string{}
+	for filename, tables := range r.fileTables {
+		for _, t := range tables {
+			if t.Table != "" {
+				tableFiles[t.Table] = filename
+			}
+		}
+	}
+
+	manifest := Manifest{
+		Package: r.loaderPackage,
+		Import:  r.options.LoaderImport(),
+		Loaders: make([]ManifestLoader, 0, len(r.structs)),
+		Skipped: r.skipped,
+	}
+	if manifest.Skipped == nil {
+		manifest.Skipped = []SkippedTable{}
+	}
+	for _, s := range r.structs {
+		pk := s.PrimaryKey()
+		table := s.QualifiedName()
+		cache := ManifestCache{Type: s.Cache.Type}
+		if s.Cache.Type == opts.CacheTypeLRU {
+			ttl, err := s.Cache.TtlDuration()
+			if err != nil {
+				return nil, fmt.Errorf("cache of %s: %w", table, err)
+			}
+			cache.Ttl = s.Cache.Ttl
+			cache.TtlSeconds = ttl.Seconds()
+			cache.Size = s.Cache.Size
+		}
+		manifest.Loaders = append(
+			manifest.Loaders, ManifestLoader{
+				Name:  s.LoaderName,
+				Table: table,
+				File:  tableFiles[table],
+				Model: s.Type().TypeWithPackage(),
+				Keys: []ManifestKey{
+					{
+						Column:   pk.DBName(),
+						Field:    pk.Name(),
+						GoType:   pk.Type().TypeWithPackage(),
+						GoImport: pk.Type().Import().Path,
+					},
+				},
+				SQL:   s.Query(),
+				Cache: cache,
+			},
+		)
+	}
+
+	contents, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("manifest error: %w", err)
+	}
+	return &plugin.File{
+		Name:     r.loaderDir() + ManifestFilename,
+		Contents: append(contents, '\n'),
+	}, nil
+}
@@ -0,0 +1,194 @@
+package renderer
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/debugger84/sqlc-dataloader/internal/imports"
+	"github.com/debugger84/sqlc-dataloader/internal/model"
+	"github.com/debugger84/sqlc-dataloader/internal/opts"
+	"github.com/iancoleman/strcase"
+	"github.com/sqlc-dev/plugin-sdk-go/plugin"
+)
+
+// ManyToManyTplData is the data of the many_to_many.tmpl template.
+// The template renders the loader of one many-to-many relation.
+type ManyToManyTplData struct {
+	// Loader is the relation the loader is rendered for.
+	Loader ManyToManyLoader
+	// Package is the name of the package of the generated code.
+	Package string
+	// Imports are the imports of the generated file.
+	Imports []imports.Import
+}
+
+// ManyToManyLoader loads the rows of the target table by the keys of the source table through the join table,
+// e.g. the tags of the posts through post_tags.
+type ManyToManyLoader struct {
+	// Name is the name of the loader without the "Loader" suffix, e.g. "TagsByPostID".
+	Name string
+	// LoaderName is the name of the loader type, e.g. "TagsByPostIDLoader".
+	LoaderName string
+	// Relation is the configured relation, e.g. "posts.id -> post_tags(post_id, tag_id) -> tags.id".
+	Relation string
+	// Target is the loaded table, e.g. tags.
+	Target model.Struct
+	// TargetKey is the key column of the target table referenced by the join table, e.g. tags.id.
+	TargetKey *model.Field
+	// Join is the join table, e.g. post_tags.
+	Join model.Struct
+	// Key is the column of the join table referencing the source table, e.g. post_tags.post_id.
+	// The loader is called with its values.
+	Key *model.Field
+	// JoinTarget is the column of the join table referencing the target table, e.g. post_tags.tag_id.
+	JoinTarget *model.Field
+	// JoinColumns are the columns of the join table returned alongside each target row.
+	JoinColumns []model.Field
+	RuntimeSettings
+}
+
+// KeyType returns the Go type of the keys, e.g. "int64".
+func (l *ManyToManyLoader) KeyType() string {
+	return l.Key.Type().String()
+}
+
+// ItemType returns the Go type of the loaded items.
+// It is the model of the target table or the generated item type with the join columns, e.g. "TagsByPostIDItem".
+func (l *ManyToManyLoader) ItemType() string {
+	if len(l.JoinColumns) > 0 {
+		return l.Name + "Item"
+	}
+	return l.Target.Type().TypeWithPackage()
+}
+
+// Query returns the SQL query selecting the key of the join table and the target rows by the keys.
+func (l *ManyToManyLoader) Query() string {
+	columns := []string{"j." + l.Key.DBName()}
+	for _, f := range l.Target.SelectedFields() {
+		columns = append(columns, "t."+f.DBName())
+	}
+	for _, f := range l.JoinColumns {
+		columns = append(columns, "j."+f.DBName())
+	}
+	return fmt.Sprintf(
+		"SELECT %s FROM %s AS t JOIN %s AS j ON j.%s = t.%s WHERE j.%s = ANY($1)",
+		strings.Join(columns, ", "),
+		l.Target.EscapedFullTableName(),
+		l.Join.EscapedFullTableName(),
+		l.JoinTarget.DBName(),
+		l.TargetKey.DBName(),
+		l.Key.DBName(),
+	)
+}
+
+// buildManyToManyLoaders finds the tables and the columns of the configured many-to-many relations.
+func (r *DataLoaderRenderer) buildManyToManyLoaders() ([]ManyToManyLoader, error) {
+	loaders := make([]ManyToManyLoader, 0, len(r.options.ManyToMany))
+	for i, m := range r.options.ManyToMany {
+		option := fmt.Sprintf("many_to_many[%d]", i)
+		loader, err := r.newManyToManyLoader(m)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", option, err)
+		}
+		loaders = append(loaders, loader)
+	}
+	return loaders, nil
+}
+
+func (r *DataLoaderRenderer) newManyToManyLoader(m opts.ManyToMany) (ManyToManyLoader, error) {
+	loader := ManyToManyLoader{Relation: m.Relation}
+	join, ok := r.findStruct(m.JoinRef)
+	if !ok {
+		return loader, fmt.Errorf("the join table %q is not found", m.Join)
+	}
+	target, ok := r.findStruct(m.TargetRef.TableRef)
+	if !ok {
+		return loader, fmt.Errorf("the target table %q is not found", m.Target)
+	}
+	loader.Join, loader.Target = join, target
+
+	var err error
+	if loader.Key, err = findField(join, m.JoinSource); err != nil {
+		return loader, err
+	}
+	if loader.JoinTarget, err = findField(join, m.JoinTarget); err != nil {
+		return loader, err
+	}
+	if loader.TargetKey, err = findField(target, m.TargetKey); err != nil {
+		return loader, err
+	}
+	for _, column := range m.JoinColumns {
+		field, err := findField(join, column)
+		if err != nil {
+			return loader, err
+		}
+		loader.JoinColumns = append(loader.JoinColumns, *field)
+	}
+	if newKeyAdapter(loader.Key, r.options) != nil {
+		return loader, fmt.Errorf(
+			"the type %s of %s.%s needs a key adapter, it is not supported by the many-to-many loaders",
+			loader.KeyType(),
+			join.RelName(),
+			m.JoinSource,
+		)
+	}
+
+	loader.Name = m.Name
+	if loader.Name == "" {
+		loader.Name = strcase.ToCamel(target.RelName()) + "By" + loader.Key.Name()
+	}
+	loader.LoaderName = loader.Name + "Loader"
+	loader.RuntimeSettings, err = newRuntimeSettings(
+		r.options.FindTableOptions(join.Schema(), join.RelName()),
+		join.FullTableName(),
+	)
+	return loader, err
+}
+
+// findStruct returns the first table matching the reference, including the tables without loaders.
+func (r *DataLoaderRenderer) findStruct(ref opts.TableRef) (model.Struct, bool) {
+	for _, s := range r.allStructs {
+		if !s.IsQuery() && ref.Matches(s.Schema(), s.RelName()) {
+			return s, true
+		}
+	}
+	return model.Struct{}, false
+}
+
+// findField returns the field of the column of the table.
+func findField(s model.Struct, column string) (*model.Field, error) {
+	fields := s.Fields()
+	for i := range fields {
+		if fields[i].DBName() == column {
+			return &fields[i], nil
+		}
+	}
+	return nil, fmt.Errorf("the column %q of %s is not found", column, s.FullTableName())
+}
+
+func (r *DataLoaderRenderer) renderManyToManyLoader(
+	tmpl *template.Template,
+	l ManyToManyLoader,
+	importer *imports.ImportBuilder,
+) (*plugin.File, error) {
+	importer = importer.AddWithAlias("github.com/debugger84/sqlc-dataloader", "dl")
+	if l.UsesTime() {
+		importer = importer.AddWithoutAlias("time")
+	}
+	importer = importer.
+		Add(l.Key.Type().Import()).
+		ImportContainer(&l.Target)
+	for _, f := range l.JoinColumns {
+		importer = importer.Add(f.Type().Import())
+	}
+	tctx := ManyToManyTplData{
+		Loader:  l,
+		Package: r.loaderPackage,
+		Imports: importer.Build(),
+	}
+
+	name := strcase.ToSnake(l.Name)
+	filename := r.loaderFilename(l.Join.Schema(), l.Join.RelName(), name)
+	return r.renderFile(tmpl, ManyToManyTemplate, filename, l.Join.QualifiedName(), &tctx)
+}
@@ -1,6 +1,116 @@
 package renderer
 
-import "embed"
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/debugger84/sqlc-dataloader/internal/opts"
+	"github.com/iancoleman/strcase"
+	"github.com/sqlc-dev/plugin-sdk-go/sdk"
+)
 
 //go:embed templates/*
 var templates embed.FS
+
+const (
+	// DataLoaderTemplate is the name of the template of a table loader.
+	// It is executed with DataLoaderTplData.
+	DataLoaderTemplate = "dataloader.tmpl"
+	// LoaderFactoryTemplate is the name of the template of the loader factory.
+	// It is executed with LoaderFactoryTplData.
+	LoaderFactoryTemplate = "loader_factory.tmpl"
+	// FakesTemplate is the name of the template of the fake loaders.
+	// It is executed with FakesTplData.
+	FakesTemplate = "fakes.tmpl"
+	// GqlgenTemplate is the name of the template of the gqlgen middleware and resolver helpers.
+	// It is executed with GqlgenTplData.
+	GqlgenTemplate = "gqlgen.tmpl"
+	// ManyToManyTemplate is the name of the template of a many-to-many loader.
+	// It is executed with ManyToManyTplData.
+	ManyToManyTemplate = "many_to_many.tmpl"
+	// CountLoaderTemplate is the name of the template of a count loader.
+	// It is executed with CountLoaderTplData.
+	CountLoaderTemplate = "count_loader.tmpl"
+	// LoaderSettingsTemplate is the name of the template of the dl.LoaderSettings field of the loader config.
+	// It is executed with RuntimeSettings.
+	LoaderSettingsTemplate = "loader_settings.tmpl"
+)
+
+// FuncMap returns the helper functions available in the built-in and the user templates.
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"lowerTitle":   sdk.LowerTitle,
+		"title":        sdk.Title,
+		"toSnake":      strcase.ToSnake,
+		"toCamel":      strcase.ToCamel,
+		"toLowerCamel": strcase.ToLowerCamel,
+		"join":         strings.Join,
+		"hasPrefix":    strings.HasPrefix,
+		"hasSuffix":    strings.HasSuffix,
+		"trimPrefix":   strings.TrimPrefix,
+		"trimSuffix":   strings.TrimSuffix,
+	}
+}
+
+// parseTemplates parses the built-in templates and overrides them by the user templates.
+// All *.tmpl files of the templates_dir are parsed, so they can define additional templates.
+// A file named as a built-in template replaces it.
+// The dataloader_template and loader_factory_template options replace the built-in templates
+// by the files with any names.
+func parseTemplates(options *opts.Options) (*template.Template, error) {
+	tmpl, err := template.New(DataLoaderTemplate).
+		Funcs(FuncMap()).
+		ParseFS(
+			templates,
+			"templates/"+DataLoaderTemplate,
+			"templates/"+LoaderFactoryTemplate,
+			"templates/"+FakesTemplate,
+			"templates/"+GqlgenTemplate,
+			"templates/"+ManyToManyTemplate,
+			"templates/"+CountLoaderTemplate,
+			"templates/"+LoaderSettingsTemplate,
+		)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.TemplatesDir != "" {
+		files, err := filepath.Glob(filepath.Join(options.TemplatesDir, "*.tmpl"))
+		if err != nil {
+			return nil, fmt.Errorf("templates_dir: %w", err)
+		}
+		for _, file := range files {
+			if tmpl, err = parseTemplateFile(tmpl, filepath.Base(file), file); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if options.DataLoaderTemplate != "" {
+		if tmpl, err = parseTemplateFile(tmpl, DataLoaderTemplate, options.DataLoaderTemplate); err != nil {
+			return nil, err
+		}
+	}
+	if options.LoaderFactoryTemplate != "" {
+		if tmpl, err = parseTemplateFile(tmpl, LoaderFactoryTemplate, options.LoaderFactoryTemplate); err != nil {
+			return nil, err
+		}
+	}
+	return tmpl, nil
+}
+
+// parseTemplateFile parses the file as the template with the given name.
+// The file may contain the template body or the {{define "name"}} block.
+func parseTemplateFile(tmpl *template.Template, name string, path string) (*template.Template, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading template %s: %w", path, err)
+	}
+	if _, err := tmpl.New(name).Parse(string(content)); err != nil {
+		return nil, fmt.Errorf("parsing template %s: %w", path, err)
+	}
+	return tmpl, nil
+}
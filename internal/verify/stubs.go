@@ -0,0 +1,454 @@
+package verify
+
+// stubs are the sources of the packages imported by the generated code.
+// The stubs of the driver and the libraries declare only the API used by the built-in templates.
+// The stubs of the packages of this module mirror their exported API, TestStubs_MatchRuntimePackages checks it.
+var stubs = map[string]string{
+	"github.com/jackc/pgx/v5": `package pgx
+
+import "github.com/jackc/pgx/v5/pgconn"
+
+var ErrNoRows error
+
+type Rows interface {
+	Close()
+	Err() error
+	FieldDescriptions() []pgconn.FieldDescription
+	Next() bool
+	Scan(dest ...any) error
+	Values() ([]any, error)
+	RawValues() [][]byte
+}
+`,
+	"github.com/jackc/pgx/v5/pgconn": `package pgconn
+
+type FieldDescription struct {
+	Name        string
+	DataTypeOID uint32
+}
+`,
+	"github.com/jackc/pgx/v5/pgtype": `package pgtype
+
+import (
+	"database/sql/driver"
+	"math/big"
+	"time"
+)
+
+type InfinityModifier int8
+
+const (
+	Infinity         InfinityModifier = 1
+	Finite           InfinityModifier = 0
+	NegativeInfinity InfinityModifier = -Infinity
+)
+
+type Numeric struct {
+	Int              *big.Int
+	Exp              int32
+	NaN              bool
+	InfinityModifier InfinityModifier
+	Valid            bool
+}
+
+func (n Numeric) Value() (driver.Value, error)
+func (n *Numeric) Scan(src any) error
+
+type Timestamptz struct {
+	Time  time.Time
+	Valid bool
+}
+`,
+	"github.com/jackc/pgtype": `package pgtype
+
+import (
+	"database/sql/driver"
+	"math/big"
+	"time"
+)
+
+type Status byte
+
+const (
+	Undefined Status = iota
+	Null
+	Present
+)
+
+type InfinityModifier int8
+
+const (
+	Infinity         InfinityModifier = 1
+	None             InfinityModifier = 0
+	NegativeInfinity InfinityModifier = -Infinity
+)
+
+type Numeric struct {
+	Int              *big.Int
+	Exp              int32
+	Status           Status
+	NaN              bool
+	InfinityModifier InfinityModifier
+}
+
+func (n Numeric) Value() (driver.Value, error)
+func (n *Numeric) Scan(src any) error
+
+type Timestamptz struct {
+	Time  time.Time
+	Status Status
+}
+`,
+	"github.com/jackc/pgx/v4": `package pgx
+
+var ErrNoRows error
+
+type Rows interface {
+	Close()
+	Err() error
+	Next() bool
+	Scan(dest ...any) error
+	Values() ([]interface{}, error)
+	RawValues() [][]byte
+}
+`,
+	"github.com/graph-gophers/dataloader/v7": `package dataloader
+
+import (
+	"context"
+	"time"
+)
+
+type Interface[K comparable, V any] interface {
+	Load(context.Context, K) Thunk[V]
+	LoadMany(context.Context, []K) ThunkMany[V]
+	Clear(context.Context, K) Interface[K, V]
+	ClearAll() Interface[K, V]
+	Prime(ctx context.Context, key K, value V) Interface[K, V]
+}
+
+type BatchFunc[K comparable, V any] func(context.Context, []K) []*Result[V]
+
+type Result[V any] struct {
+	Data  V
+	Error error
+}
+
+type ResultMany[V any] struct {
+	Data  []V
+	Error []error
+}
+
+type Thunk[V any] func() (V, error)
+
+type ThunkMany[V any] func() ([]V, []error)
+
+type Cache[K comparable, V any] interface {
+	Get(context.Context, K) (Thunk[V], bool)
+	Set(context.Context, K, Thunk[V])
+	Delete(context.Context, K) bool
+	Clear()
+}
+
+type NoCache[K comparable, V any] struct{}
+
+func (c *NoCache[K, V]) Get(context.Context, K) (Thunk[V], bool)
+func (c *NoCache[K, V]) Set(context.Context, K, Thunk[V])
+func (c *NoCache[K, V]) Delete(context.Context, K) bool
+func (c *NoCache[K, V]) Clear()
+
+type InMemoryCache[K comparable, V any] struct{}
+
+func NewCache[K comparable, V any]() *InMemoryCache[K, V] { panic("stub") }
+func (c *InMemoryCache[K, V]) Get(context.Context, K) (Thunk[V], bool)
+func (c *InMemoryCache[K, V]) Set(context.Context, K, Thunk[V])
+func (c *InMemoryCache[K, V]) Delete(context.Context, K) bool
+func (c *InMemoryCache[K, V]) Clear()
+
+type Loader[K comparable, V any] struct{}
+
+type Option[K comparable, V any] func(*Loader[K, V])
+
+func WithCache[K comparable, V any](c Cache[K, V]) Option[K, V]                { panic("stub") }
+func WithBatchCapacity[K comparable, V any](c int) Option[K, V]               { panic("stub") }
+func WithInputCapacity[K comparable, V any](c int) Option[K, V]               { panic("stub") }
+func WithWait[K comparable, V any](d time.Duration) Option[K, V]              { panic("stub") }
+func WithClearCacheOnBatch[K comparable, V any]() Option[K, V]                { panic("stub") }
+func NewBatchedLoader[K comparable, V any](batchFn BatchFunc[K, V], opts ...Option[K, V]) *Loader[K, V] {
+	panic("stub")
+}
+
+func (l *Loader[K, V]) Load(ctx context.Context, key K) Thunk[V]
+func (l *Loader[K, V]) LoadMany(ctx context.Context, keys []K) ThunkMany[V]
+func (l *Loader[K, V]) Clear(ctx context.Context, key K) Interface[K, V]
+func (l *Loader[K, V]) ClearAll() Interface[K, V]
+func (l *Loader[K, V]) Prime(ctx context.Context, key K, value V) Interface[K, V]
+`,
+	"github.com/99designs/gqlgen/graphql": `package graphql
+
+import "context"
+
+type Response struct{}
+
+type ResponseHandler func(ctx context.Context) *Response
+
+type OperationHandler func(ctx context.Context) ResponseHandler
+
+type OperationMiddleware func(ctx context.Context, next OperationHandler) ResponseHandler
+`,
+	"github.com/debugger84/sqlc-dataloader": `package sqlc_dataloader
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+const DefaultQueryConcurrency = 4
+
+type Rows interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+	Close()
+}
+
+type Scanner interface {
+	Scan(dest ...any) error
+}
+
+type QueryFunc func(ctx context.Context, query string, args ...any) (Rows, error)
+
+type TableLoaderConfig[K comparable, V any] struct {
+	Table      string
+	Query      string
+	DB         QueryFunc
+	Scan       func(row Scanner) (V, error)
+	ScanColumn func(item *V, column string) any
+	Columns    func(rows Rows) ([]string, error)
+	Key        func(item V) K
+	Args        func(keys []K) any
+	Cache       dataloader.Cache[K, V]
+	ExistsQuery string
+	ExistsCache dataloader.Cache[K, bool]
+	LoaderSettings
+}
+
+type LoaderSettings struct {
+	MaxKeysPerQuery  int
+	QueryConcurrency int
+	QueryTimeout     time.Duration
+	Retry            *RetryPolicy
+	DuplicateKeys    DuplicateKeyPolicy
+}
+
+type DuplicateKeyPolicy string
+
+const (
+	DuplicateKeysWarn  DuplicateKeyPolicy = "warn"
+	DuplicateKeysError DuplicateKeyPolicy = "error"
+	DuplicateKeysFirst DuplicateKeyPolicy = "first"
+)
+
+func WithDuplicateKeys(policy DuplicateKeyPolicy) LoaderOption { panic("stub") }
+
+type DuplicateKeyError struct {
+	Table string
+	Key   any
+	Rows  int
+}
+
+func (e *DuplicateKeyError) Error() string
+
+type RetryPolicy struct {
+	MaxAttempts   int
+	BaseDelay     time.Duration
+	MaxDelay      time.Duration
+	Retryable     func(err error) bool
+	RetryTimeouts bool
+	Budget        *RetryBudget
+	Metrics       *RetryMetrics
+	OnRetry       func(ctx context.Context, event RetryEvent)
+}
+
+type RetryOutcome string
+
+const (
+	RetryScheduled      RetryOutcome = "retry"
+	RetryExhausted      RetryOutcome = "exhausted"
+	RetryBudgetExceeded RetryOutcome = "budget_exceeded"
+)
+
+const (
+	DefaultRetryMaxAttempts = 3
+	DefaultRetryBaseDelay   = 50 * time.Millisecond
+	DefaultRetryMaxDelay    = time.Second
+)
+
+type RetryEvent struct {
+	Table   string
+	Attempt int
+	Delay   time.Duration
+	Err     error
+	Outcome RetryOutcome
+}
+
+type RetryBudget struct{}
+
+func NewRetryBudget(maxRetries int, ratio float64) *RetryBudget { panic("stub") }
+
+type RetryMetrics struct{}
+
+type RetryStats struct {
+	Retries        int64
+	Exhausted      int64
+	BudgetExceeded int64
+}
+
+func (m *RetryMetrics) Stats() RetryStats { panic("stub") }
+
+func WithRetryPolicy(policy *RetryPolicy) LoaderOption { panic("stub") }
+func IsRetryable(err error) bool                      { panic("stub") }
+
+type LoaderOption func(settings *LoaderSettings)
+
+func WithQueryTimeout(timeout time.Duration) LoaderOption { panic("stub") }
+
+type TableLoader[K comparable, V any] struct{}
+
+func NewTableLoader[K comparable, V any](config TableLoaderConfig[K, V], options ...LoaderOption) *TableLoader[K, V] {
+	panic("stub")
+}
+func (l *TableLoader[K, V]) Load(ctx context.Context, key K) (V, error)
+func (l *TableLoader[K, V]) LoadMany(ctx context.Context, keys []K) ([]V, []error)
+func (l *TableLoader[K, V]) Clear(ctx context.Context, key K)
+func (l *TableLoader[K, V]) Prime(ctx context.Context, key K, value V)
+func (l *TableLoader[K, V]) Exists(ctx context.Context, key K) (bool, error)
+func (l *TableLoader[K, V]) ExistsMany(ctx context.Context, keys []K) ([]bool, []error)
+
+type GroupLoaderConfig[K comparable, V any] struct {
+	Table string
+	Query string
+	DB    QueryFunc
+	Scan  func(row Scanner) (K, V, error)
+	Args  func(keys []K) any
+	Cache dataloader.Cache[K, []V]
+	LoaderSettings
+}
+
+type GroupLoader[K comparable, V any] struct{}
+
+func NewGroupLoader[K comparable, V any](config GroupLoaderConfig[K, V], options ...LoaderOption) *GroupLoader[K, V] {
+	panic("stub")
+}
+func (l *GroupLoader[K, V]) Load(ctx context.Context, key K) ([]V, error)
+func (l *GroupLoader[K, V]) LoadMany(ctx context.Context, keys []K) ([][]V, []error)
+func (l *GroupLoader[K, V]) Clear(ctx context.Context, key K)
+func (l *GroupLoader[K, V]) Prime(ctx context.Context, key K, group []V)
+
+type CountLoaderConfig[K comparable] struct {
+	Table string
+	Query string
+	DB    QueryFunc
+	Args  func(keys []K) any
+	Cache dataloader.Cache[K, int64]
+	LoaderSettings
+}
+
+type CountLoader[K comparable] struct{}
+
+func NewCountLoader[K comparable](config CountLoaderConfig[K], options ...LoaderOption) *CountLoader[K] {
+	panic("stub")
+}
+func (l *CountLoader[K]) Load(ctx context.Context, key K) (int64, error)
+func (l *CountLoader[K]) LoadMany(ctx context.Context, keys []K) ([]int64, []error)
+func (l *CountLoader[K]) Clear(ctx context.Context, key K)
+func (l *CountLoader[K]) Prime(ctx context.Context, key K, count int64)
+
+type KeyAdapter[P any, E comparable] struct {
+	Encode func(key P) E
+	Decode func(key E) P
+}
+
+func (a KeyAdapter[P, E]) Normalized(normalize func(key P) P) KeyAdapter[P, E] { panic("stub") }
+
+func BytesKeyAdapter[P ~[]byte]() KeyAdapter[P, string]                              { panic("stub") }
+func NormalizedKeyAdapter[P comparable](normalize func(key P) P) KeyAdapter[P, P] { panic("stub") }
+func LowerKey(key string) string                                                    { panic("stub") }
+func NormalizeTime(key time.Time) time.Time                                         { panic("stub") }
+func NormalizeDecimal(key string) string                                            { panic("stub") }
+func DecimalKey(value *big.Int, exp int32) string                                   { panic("stub") }
+func ParseDecimalKey(key string) (*big.Int, int32)                                  { panic("stub") }
+
+type KeyedLoader[P any, E comparable, V any] struct{}
+
+func NewKeyedLoader[P any, E comparable, V any](
+	config TableLoaderConfig[E, V],
+	adapter KeyAdapter[P, E],
+	options ...LoaderOption,
+) *KeyedLoader[P, E, V] {
+	panic("stub")
+}
+func (l *KeyedLoader[P, E, V]) Load(ctx context.Context, key P) (V, error)
+func (l *KeyedLoader[P, E, V]) LoadMany(ctx context.Context, keys []P) ([]V, []error)
+func (l *KeyedLoader[P, E, V]) Clear(ctx context.Context, key P)
+func (l *KeyedLoader[P, E, V]) Prime(ctx context.Context, key P, value V)
+func (l *KeyedLoader[P, E, V]) Exists(ctx context.Context, key P) (bool, error)
+func (l *KeyedLoader[P, E, V]) ExistsMany(ctx context.Context, keys []P) ([]bool, []error)
+
+var ErrNoRows error
+
+type QueryTimeoutError struct {
+	Timeout time.Duration
+	Err     error
+}
+
+func (e *QueryTimeoutError) Error() string
+func (e *QueryTimeoutError) Unwrap() []error
+
+type TableInfo struct {
+	Table     string
+	KeyColumn string
+	KeyType   string
+}
+
+type AnyLoader interface {
+	Info() TableInfo
+	LoadAny(ctx context.Context, key any) (any, error)
+	LoadManyAny(ctx context.Context, keys []any) ([]any, []error)
+}
+
+type TypedLoader[K any, V any] interface {
+	Load(ctx context.Context, key K) (V, error)
+	LoadMany(ctx context.Context, keys []K) ([]V, []error)
+}
+
+type KeyTypeError struct {
+	Info TableInfo
+	Key  any
+}
+
+func (e *KeyTypeError) Error() string
+
+func NewAnyLoader[K any, V any](info TableInfo, loader TypedLoader[K, V]) AnyLoader { panic("stub") }
+`,
+	"github.com/debugger84/sqlc-dataloader/cache": `package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+type LRU[K comparable, V any] struct{}
+
+func NewLRU[K comparable, V any](size int, ttl time.Duration) *LRU[K, V] { panic("stub") }
+func (c *LRU[K, V]) Get(_ context.Context, key K) (dataloader.Thunk[V], bool)
+func (c *LRU[K, V]) Set(_ context.Context, key K, value dataloader.Thunk[V])
+func (c *LRU[K, V]) Delete(_ context.Context, key K) bool
+func (c *LRU[K, V]) Clear()
+`,
+}
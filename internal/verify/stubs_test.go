@@ -0,0 +1,94 @@
+package verify
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+	"testing"
+
+	stdimporter "go/importer"
+
+	"github.com/stretchr/testify/require"
+)
+
+// runtimePackages are the packages of this module mirrored by the stubs.
+var runtimePackages = []string{
+	"github.com/debugger84/sqlc-dataloader",
+	"github.com/debugger84/sqlc-dataloader/cache",
+}
+
+func TestStubs_MatchRuntimePackages(t *testing.T) {
+	fset := token.NewFileSet()
+	source := stdimporter.ForCompiler(fset, "source", nil)
+	stubbed := &importer{
+		fset:      fset,
+		generated: map[string][]*ast.File{},
+		sources:   stubs,
+		types:     map[string]map[string]struct{}{},
+		names:     map[string]string{},
+		packages:  map[string]*types.Package{},
+		std:       stdimporter.Default(),
+	}
+
+	for _, pkgPath := range runtimePackages {
+		t.Run(pkgPath, func(t *testing.T) {
+			pkg, err := source.Import(pkgPath)
+			require.NoError(t, err)
+			stub, err := stubbed.Import(pkgPath)
+			require.NoError(t, err)
+
+			require.Equal(t, exportedAPI(pkg), exportedAPI(stub), "the stub of %s differs from the package", pkgPath)
+		})
+	}
+}
+
+// exportedAPI returns the declarations of the exported objects, the exported fields and the methods of the package.
+// The types of other packages are qualified by the package names.
+func exportedAPI(pkg *types.Package) []string {
+	qualifier := func(p *types.Package) string {
+		if p == pkg {
+			return ""
+		}
+		return p.Name()
+	}
+	var api []string
+	for _, name := range pkg.Scope().Names() {
+		obj := pkg.Scope().Lookup(name)
+		if !obj.Exported() {
+			continue
+		}
+		typeName, ok := obj.(*types.TypeName)
+		if !ok {
+			api = append(api, types.ObjectString(obj, qualifier))
+			continue
+		}
+		named, ok := typeName.Type().(*types.Named)
+		if !ok {
+			api = append(api, types.ObjectString(obj, qualifier))
+			continue
+		}
+		switch underlying := named.Underlying().(type) {
+		case *types.Struct:
+			api = append(api, "type "+types.TypeString(named, qualifier)+" struct")
+			for i := 0; i < underlying.NumFields(); i++ {
+				if field := underlying.Field(i); field.Exported() {
+					api = append(api, name+"."+types.ObjectString(field, qualifier))
+				}
+			}
+		default:
+			api = append(api, types.ObjectString(obj, qualifier))
+		}
+		for i := 0; i < named.NumMethods(); i++ {
+			if method := named.Method(i); method.Exported() {
+				api = append(api, types.ObjectString(method, qualifier))
+			}
+		}
+	}
+	sort.Strings(api)
+	for i, decl := range api {
+		api[i] = strings.ReplaceAll(decl, "interface{}", "any")
+	}
+	return api
+}
@@ -0,0 +1,311 @@
+package verify
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	stdimporter "go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/debugger84/sqlc-dataloader/internal/gotype"
+	"github.com/debugger84/sqlc-dataloader/internal/imports"
+	"github.com/debugger84/sqlc-dataloader/internal/model"
+	"github.com/debugger84/sqlc-dataloader/internal/opts"
+	"github.com/sqlc-dev/plugin-sdk-go/plugin"
+)
+
+// Verifier type-checks the generated code.
+// The model package is stubbed from the structs, the driver and the libraries
+// used by the generated code are stubbed by the hand-written declarations.
+// The standard library is loaded from the export data of the Go installation.
+type Verifier struct {
+	structs []model.Struct
+	options *opts.Options
+}
+
+func NewVerifier(structs []model.Struct, options *opts.Options) *Verifier {
+	return &Verifier{
+		structs: structs,
+		options: options,
+	}
+}
+
+// TableLocator finds the table the line of a generated file is rendered for.
+type TableLocator interface {
+	Table(filename string, line int) (string, bool)
+}
+
+// Verify type-checks the generated Go files.
+// The tables are used to add the table names to the errors.
+// All found problems are returned as one error.
+func (v *Verifier) Verify(files []*plugin.File, tables TableLocator) error {
+	imp := &importer{
+		fset:      token.NewFileSet(),
+		generated: map[string][]*ast.File{},
+		sources:   map[string]string{},
+		types:     map[string]map[string]struct{}{},
+		names:     map[string]string{},
+		packages:  map[string]*types.Package{},
+		std:       stdimporter.Default(),
+		tables:    tables,
+	}
+	for p, src := range stubs {
+		imp.sources[p] = src
+	}
+
+	for _, f := range files {
+		if !strings.HasSuffix(f.Name, ".go") {
+			continue
+		}
+		file, err := parser.ParseFile(imp.fset, f.Name, f.Contents, 0)
+		if err != nil {
+			imp.addError(f.Name, 0, err)
+			continue
+		}
+		pkgPath := v.packagePath(path.Dir(f.Name))
+		imp.generated[pkgPath] = append(imp.generated[pkgPath], file)
+	}
+
+	// Without the model import, the model types are not qualified, so they are expected in the package of the loaders.
+	modelPath := v.packagePath(".")
+	if loaderPath := v.packagePath(v.options.Package); v.options.ModelImport == "" && imp.generated[loaderPath] != nil {
+		modelPath = loaderPath
+	}
+	if generated, ok := imp.generated[modelPath]; ok {
+		src := v.modelSource(generated[0].Name.Name, imp)
+		file, err := parser.ParseFile(imp.fset, "model_stub.go", src, 0)
+		if err != nil {
+			return fmt.Errorf("model stub: %w", err)
+		}
+		imp.generated[modelPath] = append(generated, file)
+	} else {
+		imp.sources[modelPath] = v.modelSource(path.Base(modelPath), imp)
+	}
+
+	paths := make([]string, 0, len(imp.generated))
+	for p := range imp.generated {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		if _, err := imp.Import(p); err != nil {
+			return err
+		}
+	}
+
+	if len(imp.errs) > 0 {
+		return fmt.Errorf("the generated code does not compile:\n%w", errors.Join(imp.errs...))
+	}
+	return nil
+}
+
+// packagePath returns the import path of the package in the directory relative to the output directory.
+func (v *Verifier) packagePath(dir string) string {
+	base := v.options.ModelImport
+	if base == "" {
+		base = "generated"
+	}
+	if dir == "." || dir == "" {
+		return base
+	}
+	return base + "/" + dir
+}
+
+// modelSource returns the stub of the model package with the structs and the DBTX interface.
+// The types of the fields from other packages are registered in the importer to be stubbed.
+func (v *Verifier) modelSource(pkgName string, imp *importer) string {
+	modelPkg := ""
+	if len(v.structs) > 0 {
+		modelPkg = v.structs[0].Type().PackageName()
+	}
+	qualifier := regexp.MustCompile(`\b` + regexp.QuoteMeta(modelPkg) + `\.`)
+
+	importLines := map[string]string{
+		"context": `"context"`,
+	}
+	dbtx := ""
+	switch v.options.Driver() {
+	case opts.SQLDriverPGXV5, opts.SQLDriverPGXV4:
+		importLines[string(v.options.Driver())] = fmt.Sprintf(`pgx "%s"`, v.options.Driver())
+		dbtx = "Query(context.Context, string, ...interface{}) (pgx.Rows, error)"
+	default:
+		importLines["database/sql"] = `sql "database/sql"`
+		dbtx = "QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error)"
+	}
+
+	var decls strings.Builder
+	localTypes := map[string]struct{}{}
+	for _, s := range v.structs {
+		decls.WriteString(fmt.Sprintf("type %s struct {\n", s.Type().TypeName()))
+		for _, f := range s.Fields() {
+			goType := f.Type()
+			typeName := goType.String()
+			switch {
+			case goType.PackageName() == modelPkg && goType.Import().Path == "" &&
+				token.IsIdentifier(goType.TypeName()) && types.Universe.Lookup(goType.TypeName()) == nil:
+				typeName = qualifier.ReplaceAllString(typeName, "")
+				localTypes[goType.TypeName()] = struct{}{}
+			case goType.Import().Path != "":
+				importLines[goType.Import().Path] = fmt.Sprintf(`%s "%s"`, goType.PackageName(), goType.Import().Path)
+				imp.addType(goType.Import().Path, goType.PackageName(), goType.TypeName())
+			}
+			decls.WriteString(fmt.Sprintf("\t%s %s\n", f.Name(), typeName))
+		}
+		decls.WriteString("}\n\n")
+	}
+	for _, s := range v.structs {
+		delete(localTypes, s.Type().TypeName())
+	}
+	for name := range localTypes {
+		decls.WriteString(fmt.Sprintf("type %s string\n\n", name))
+	}
+
+	lines := make([]string, 0, len(importLines))
+	for _, line := range importLines {
+		lines = append(lines, line)
+	}
+	sort.Strings(lines)
+
+	return fmt.Sprintf(
+		"package %s\n\nimport (\n\t%s\n)\n\ntype DBTX interface {\n\t%s\n}\n\n%s",
+		pkgName,
+		strings.Join(lines, "\n\t"),
+		dbtx,
+		decls.String(),
+	)
+}
+
+type importer struct {
+	fset *token.FileSet
+	// generated are the parsed generated files by the package paths.
+	generated map[string][]*ast.File
+	// sources are the sources of the stubbed packages.
+	sources map[string]string
+	// types are the type names referenced by the model, they are added to the stubbed packages.
+	types map[string]map[string]struct{}
+	// names are the names of the stubbed packages without sources.
+	names    map[string]string
+	packages map[string]*types.Package
+	// std imports the packages of the standard library.
+	std    types.Importer
+	tables TableLocator
+	errs   []error
+}
+
+func (i *importer) addType(pkgPath, pkgName, typeName string) {
+	if i.types[pkgPath] == nil {
+		i.types[pkgPath] = map[string]struct{}{}
+	}
+	i.types[pkgPath][typeName] = struct{}{}
+	i.names[pkgPath] = pkgName
+}
+
+func (i *importer) addError(filename string, line int, err error) {
+	if table, ok := i.tables.Table(filename, line); ok {
+		err = fmt.Errorf("%w (table %s)", err, table)
+	}
+	i.errs = append(i.errs, err)
+}
+
+func (i *importer) Import(pkgPath string) (*types.Package, error) {
+	if pkg, ok := i.packages[pkgPath]; ok {
+		if pkg == nil {
+			return nil, fmt.Errorf("import cycle through %s", pkgPath)
+		}
+		return pkg, nil
+	}
+	files, generated := i.generated[pkgPath]
+	if _, stubbed := i.sources[pkgPath]; !generated && !stubbed && isStd(pkgPath) {
+		if pkg, err := i.std.Import(pkgPath); err == nil {
+			i.packages[pkgPath] = pkg
+			return pkg, nil
+		}
+	}
+	i.packages[pkgPath] = nil
+	if !generated {
+		var err error
+		if files, err = i.stubFiles(pkgPath); err != nil {
+			return nil, err
+		}
+	}
+
+	var stubErr error
+	conf := types.Config{
+		Importer: i,
+		Error: func(err error) {
+			if !generated {
+				stubErr = errors.Join(stubErr, err)
+				return
+			}
+			var typeErr types.Error
+			if errors.As(err, &typeErr) {
+				position := typeErr.Fset.Position(typeErr.Pos)
+				i.addError(position.Filename, position.Line, err)
+				return
+			}
+			i.errs = append(i.errs, err)
+		},
+	}
+	pkg, _ := conf.Check(pkgPath, i.fset, files, nil)
+	if stubErr != nil {
+		return nil, fmt.Errorf("stub of %s: %w", pkgPath, stubErr)
+	}
+	i.packages[pkgPath] = pkg
+	return pkg, nil
+}
+
+// stubFiles returns the stub of the package with the referenced types that are not declared in the stub source.
+func (i *importer) stubFiles(pkgPath string) ([]*ast.File, error) {
+	var files []*ast.File
+	declared := map[string]struct{}{}
+	pkgName := i.names[pkgPath]
+	if src, ok := i.sources[pkgPath]; ok {
+		file, err := parser.ParseFile(i.fset, pkgPath+"/stub.go", src, 0)
+		if err != nil {
+			return nil, fmt.Errorf("stub of %s: %w", pkgPath, err)
+		}
+		files = append(files, file)
+		pkgName = file.Name.Name
+		for name := range file.Scope.Objects {
+			declared[name] = struct{}{}
+		}
+	}
+	if pkgName == "" {
+		pkgName = imports.PackageName(pkgPath)
+	}
+
+	var src strings.Builder
+	src.WriteString(fmt.Sprintf("package %s\n\n", pkgName))
+	names := make([]string, 0, len(i.types[pkgPath]))
+	for name := range i.types[pkgPath] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := declared[name]; ok {
+			continue
+		}
+		if gotype.HasValidField(pkgPath, name) {
+			src.WriteString(fmt.Sprintf("type %s struct{ Valid bool }\n", name))
+		} else {
+			src.WriteString(fmt.Sprintf("type %s struct{}\n", name))
+		}
+	}
+	file, err := parser.ParseFile(i.fset, pkgPath+"/types_stub.go", src.String(), 0)
+	if err != nil {
+		return nil, fmt.Errorf("stub of %s: %w", pkgPath, err)
+	}
+	return append(files, file), nil
+}
+
+// isStd returns true if the path is the path of a standard library package, e.g. "context" or "math/big".
+func isStd(pkgPath string) bool {
+	first, _, _ := strings.Cut(pkgPath, "/")
+	return !strings.Contains(first, ".")
+}
@@ -0,0 +1,208 @@
+package sqlc_dataloader
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// KeyAdapter maps the keys of a loader to the comparable keys of the batches and the cache.
+// It is used for the keys that are not comparable, e.g. []byte, or have to be normalised
+// to match the values returned by the database, e.g. citext or timestamptz.
+type KeyAdapter[P any, E comparable] struct {
+	// Encode returns the comparable key. The keys of the loaded rows are encoded too.
+	Encode func(key P) E
+	// Decode returns the key passed to the query by the encoded key.
+	Decode func(key E) P
+}
+
+// BytesKeyAdapter maps the byte slices, e.g. bytea or json.RawMessage, to the strings.
+func BytesKeyAdapter[P ~[]byte]() KeyAdapter[P, string] {
+	return KeyAdapter[P, string]{
+		Encode: func(key P) string {
+			return string(key)
+		},
+		Decode: func(key string) P {
+			return P(key)
+		},
+	}
+}
+
+// Normalized returns the adapter normalising the keys before they are encoded.
+func (a KeyAdapter[P, E]) Normalized(normalize func(key P) P) KeyAdapter[P, E] {
+	encode := a.Encode
+	return KeyAdapter[P, E]{
+		Encode: func(key P) E {
+			return encode(normalize(key))
+		},
+		Decode: a.Decode,
+	}
+}
+
+// NormalizedKeyAdapter normalises the comparable keys. The normalised key is passed to the query.
+func NormalizedKeyAdapter[P comparable](normalize func(key P) P) KeyAdapter[P, P] {
+	return KeyAdapter[P, P]{
+		Encode: normalize,
+		Decode: func(key P) P {
+			return key
+		},
+	}
+}
+
+// LowerKey normalises the case-insensitive keys, e.g. citext.
+func LowerKey(key string) string {
+	return strings.ToLower(key)
+}
+
+// NormalizeTime normalises the time keys to the UTC time rounded to microseconds as PostgreSQL stores them.
+func NormalizeTime(key time.Time) time.Time {
+	return key.UTC().Round(time.Microsecond)
+}
+
+// NormalizeDecimal normalises the text of a decimal key, e.g. "01.50" and "150e-2" to "1.5".
+// The text that is not a decimal number is returned as is.
+func NormalizeDecimal(key string) string {
+	value, exp, ok := parseDecimal(strings.TrimSpace(key))
+	if !ok {
+		return key
+	}
+	return DecimalKey(value, exp)
+}
+
+// DecimalKey formats the decimal value*10^exp, e.g. the Int and the Exp of pgtype.Numeric,
+// as the text without the trailing zeros, so 15e-1 and 150e-2 are both "1.5".
+func DecimalKey(value *big.Int, exp int32) string {
+	if value == nil || value.Sign() == 0 {
+		return "0"
+	}
+	ten := big.NewInt(10)
+	digits, rem := new(big.Int).Set(value), new(big.Int)
+	for {
+		quo, _ := new(big.Int).QuoRem(digits, ten, rem)
+		if rem.Sign() != 0 {
+			break
+		}
+		digits = quo
+		exp++
+	}
+	sign := ""
+	if digits.Sign() < 0 {
+		sign = "-"
+		digits.Neg(digits)
+	}
+	text := digits.String()
+	if exp >= 0 {
+		return sign + text + strings.Repeat("0", int(exp))
+	}
+	scale := int(-exp)
+	if len(text) <= scale {
+		text = strings.Repeat("0", scale-len(text)+1) + text
+	}
+	return sign + text[:len(text)-scale] + "." + text[len(text)-scale:]
+}
+
+// ParseDecimalKey returns the digits and the exponent of the key returned by DecimalKey.
+// The text that is not a decimal number is parsed as zero.
+func ParseDecimalKey(key string) (*big.Int, int32) {
+	value, exp, ok := parseDecimal(key)
+	if !ok {
+		return new(big.Int), 0
+	}
+	return value, exp
+}
+
+// parseDecimal parses the decimal text in the plain or the exponent form, e.g. "-1.50" or "150e-2".
+func parseDecimal(s string) (*big.Int, int32, bool) {
+	mantissa, exponent, hasExp := strings.Cut(strings.ToLower(s), "e")
+	exp := int64(0)
+	if hasExp {
+		var err error
+		if exp, err = strconv.ParseInt(exponent, 10, 32); err != nil {
+			return nil, 0, false
+		}
+	}
+	sign := ""
+	if strings.HasPrefix(mantissa, "-") || strings.HasPrefix(mantissa, "+") {
+		sign = mantissa[:1]
+		mantissa = mantissa[1:]
+	}
+	integer, fraction, _ := strings.Cut(mantissa, ".")
+	if integer+fraction == "" || !isDigits(integer) || !isDigits(fraction) {
+		return nil, 0, false
+	}
+	exp -= int64(len(fraction))
+	if exp < math.MinInt32 || exp > math.MaxInt32 {
+		return nil, 0, false
+	}
+	value, ok := new(big.Int).SetString(sign+integer+fraction, 10)
+	return value, int32(exp), ok
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// KeyedLoader is TableLoader with the keys mapped by KeyAdapter.
+// The results are returned for the original keys of the callers.
+type KeyedLoader[P any, E comparable, V any] struct {
+	loader  *TableLoader[E, V]
+	adapter KeyAdapter[P, E]
+}
+
+// NewKeyedLoader creates the loader. The Key function of the config must return the encoded key of the item.
+// The decoded keys are passed to the query.
+func NewKeyedLoader[P any, E comparable, V any](
+	config TableLoaderConfig[E, V],
+	adapter KeyAdapter[P, E],
+	options ...LoaderOption,
+) *KeyedLoader[P, E, V] {
+	config.Args = func(keys []E) any {
+		decoded := make([]P, len(keys))
+		for i, key := range keys {
+			decoded[i] = adapter.Decode(key)
+		}
+		return decoded
+	}
+	config.scanKey = func(row Scanner) (E, error) {
+		var key P
+		err := row.Scan(&key)
+		return adapter.Encode(key), err
+	}
+	return &KeyedLoader[P, E, V]{
+		loader:  NewTableLoader(config, options...),
+		adapter: adapter,
+	}
+}
+
+// Load loads the item by the key. It returns ErrNoRows if there is no row with the key.
+func (l *KeyedLoader[P, E, V]) Load(ctx context.Context, key P) (V, error) {
+	return l.loader.Load(ctx, l.adapter.Encode(key))
+}
+
+// LoadMany loads the items by the keys. The errors are nil if all items are loaded,
+// otherwise they contain ErrNoRows for the missing keys.
+func (l *KeyedLoader[P, E, V]) LoadMany(ctx context.Context, keys []P) ([]V, []error) {
+	encoded := make([]E, len(keys))
+	for i, key := range keys {
+		encoded[i] = l.adapter.Encode(key)
+	}
+	return l.loader.LoadMany(ctx, encoded)
+}
+
+// Clear removes the item from the cache.
+func (l *KeyedLoader[P, E, V]) Clear(ctx context.Context, key P) {
+	l.loader.Clear(ctx, l.adapter.Encode(key))
+}
+
+// Prime adds the item to the cache if there is no item with the key.
+func (l *KeyedLoader[P, E, V]) Prime(ctx context.Context, key P, value V) {
+	l.loader.Prime(ctx, l.adapter.Encode(key), value)
+}
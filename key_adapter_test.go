@@ -0,0 +1,163 @@
+package sqlc_dataloader_test
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	dl "github.com/debugger84/sqlc-dataloader"
+	"github.com/stretchr/testify/require"
+)
+
+type file struct {
+	Hash []byte
+	Name string
+}
+
+// fileRows are the rows of the files scanned to the hash and the name.
+type fileRows struct {
+	files []file
+	next  int
+}
+
+func (r *fileRows) Next() bool {
+	r.next++
+	return r.next <= len(r.files)
+}
+
+func (r *fileRows) Scan(dest ...any) error {
+	*dest[0].(*[]byte) = r.files[r.next-1].Hash
+	*dest[1].(*string) = r.files[r.next-1].Name
+	return nil
+}
+
+func (r *fileRows) Err() error {
+	return nil
+}
+
+func (r *fileRows) Close() {}
+
+func TestKeyedLoader_Bytes(t *testing.T) {
+	files := []file{{Hash: []byte{1, 2}, Name: "a.txt"}, {Hash: []byte{3}, Name: "b.txt"}}
+	var queried [][]byte
+	loader := dl.NewKeyedLoader(
+		dl.TableLoaderConfig[string, file]{
+			Query: "SELECT hash, name FROM files WHERE hash = ANY($1)",
+			DB: func(_ context.Context, _ string, args ...any) (dl.Rows, error) {
+				queried = args[0].([][]byte)
+				return &fileRows{files: files}, nil
+			},
+			Scan: func(row dl.Scanner) (file, error) {
+				var f file
+				err := row.Scan(&f.Hash, &f.Name)
+				return f, err
+			},
+			Key: func(f file) string {
+				return string(f.Hash)
+			},
+		},
+		dl.BytesKeyAdapter[[]byte](),
+	)
+
+	result, errs := loader.LoadMany(context.Background(), [][]byte{{3}, {1, 2}, {4}})
+
+	require.Equal(t, []file{files[1], files[0], {}}, result)
+	require.NoError(t, errs[0])
+	require.NoError(t, errs[1])
+	require.ErrorIs(t, errs[2], dl.ErrNoRows)
+	require.ElementsMatch(t, [][]byte{{3}, {1, 2}, {4}}, queried, "the decoded keys are passed to the query")
+}
+
+func TestKeyedLoader_Normalized(t *testing.T) {
+	var queried []string
+	loader := dl.NewKeyedLoader(
+		dl.TableLoaderConfig[string, user]{
+			Query: "SELECT id, name FROM users WHERE name = ANY($1)",
+			DB: func(_ context.Context, _ string, args ...any) (dl.Rows, error) {
+				queried = args[0].([]string)
+				return &fakeRows{rows: []user{{ID: 1, Name: "john"}}}, nil
+			},
+			Scan: func(row dl.Scanner) (user, error) {
+				var u user
+				err := row.Scan(&u.ID, &u.Name)
+				return u, err
+			},
+			Key: func(u user) string {
+				return dl.LowerKey(u.Name)
+			},
+		},
+		dl.NormalizedKeyAdapter(dl.LowerKey),
+	)
+
+	result, errs := loader.LoadMany(context.Background(), []string{"John", "JOHN"})
+
+	require.Nil(t, errs)
+	require.Equal(t, []user{{ID: 1, Name: "john"}, {ID: 1, Name: "john"}}, result, "the results are mapped back to the original keys")
+	require.Equal(t, []string{"john"}, queried)
+}
+
+func TestKeyAdapter_Normalized(t *testing.T) {
+	adapter := dl.BytesKeyAdapter[[]byte]().Normalized(func(key []byte) []byte {
+		return bytes.ToLower(key)
+	})
+	require.Equal(t, "abc", adapter.Encode([]byte("ABC")))
+	require.Equal(t, []byte("abc"), adapter.Decode(adapter.Encode([]byte("aBc"))))
+}
+
+func TestNormalizeTime(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	local := time.Date(2024, 1, 1, 15, 0, 0, 1_999, moscow)
+	require.Equal(t, time.Date(2024, 1, 1, 12, 0, 0, 2_000, time.UTC), dl.NormalizeTime(local))
+}
+
+func TestNormalizeDecimal(t *testing.T) {
+	for _, test := range []struct {
+		value    string
+		expected string
+	}{
+		{value: "1.50", expected: "1.5"},
+		{value: "001.0", expected: "1"},
+		{value: "+0.250", expected: "0.25"},
+		{value: "-.5", expected: "-0.5"},
+		{value: "-0.00", expected: "0"},
+		{value: "120", expected: "120"},
+		{value: "1e3", expected: "1000"},
+		{value: "15e-1", expected: "1.5"},
+		{value: "150E-2", expected: "1.5"},
+		{value: "1.5e", expected: "1.5e"},
+		{value: "NaN", expected: "NaN"},
+	} {
+		tt := test
+		t.Run(strings.ReplaceAll(tt.value, ".", "_"), func(t *testing.T) {
+			require.Equal(t, tt.expected, dl.NormalizeDecimal(tt.value))
+		})
+	}
+}
+
+func TestDecimalKey(t *testing.T) {
+	// The digits and the exponents as pgtype.Numeric holds them after the scan of the numeric columns.
+	for _, test := range []struct {
+		value    int64
+		exp      int32
+		expected string
+	}{
+		{value: 15, exp: -1, expected: "1.5"},
+		{value: 150, exp: -2, expected: "1.5"},
+		{value: -25, exp: -3, expected: "-0.025"},
+		{value: 12, exp: 1, expected: "120"},
+		{value: 1200, exp: -2, expected: "12"},
+		{value: 0, exp: -2, expected: "0"},
+	} {
+		tt := test
+		t.Run(tt.expected, func(t *testing.T) {
+			key := dl.DecimalKey(big.NewInt(tt.value), tt.exp)
+			require.Equal(t, tt.expected, key)
+
+			value, exp := dl.ParseDecimalKey(key)
+			require.Equal(t, key, dl.DecimalKey(value, exp))
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package sqlc_dataloader
+
+import "time"
+
+// LoaderSettings are the settings of TableLoader that can be overridden at runtime by the LoaderOption functions.
+type LoaderSettings struct {
+	// MaxKeysPerQuery splits the batches with more keys into several queries. There is no limit if it is 0.
+	MaxKeysPerQuery int
+	// QueryConcurrency is the number of the parallel queries of one batch, DefaultQueryConcurrency if it is 0.
+	QueryConcurrency int
+	// QueryTimeout bounds the context of each query of a batch. There is no timeout if it is 0.
+	QueryTimeout time.Duration
+	// Retry retries the failed queries of a batch. The queries are not retried if it is nil.
+	Retry *RetryPolicy
+	// DuplicateKeys is the handling of the keys with more than one row, DuplicateKeysWarn if it is empty.
+	DuplicateKeys DuplicateKeyPolicy
+}
+
+// DuplicateKeyPolicy is the handling of the keys with more than one row.
+type DuplicateKeyPolicy string
+
+const (
+	// DuplicateKeysWarn logs a warning by slog and returns the first row of the key as DuplicateKeysFirst does.
+	DuplicateKeysWarn DuplicateKeyPolicy = "warn"
+	// DuplicateKeysError returns the DuplicateKeyError for the key.
+	DuplicateKeysError DuplicateKeyPolicy = "error"
+	// DuplicateKeysFirst silently returns the first row of the key in the order of the query result.
+	// The queries are not ordered, so the returned row is arbitrary and can differ between the queries.
+	DuplicateKeysFirst DuplicateKeyPolicy = "first"
+)
+
+// LoaderOption overrides the generated settings of a loader.
+// The options are passed to the constructors of the generated loaders and to the loader factory.
+type LoaderOption func(settings *LoaderSettings)
+
+// WithQueryTimeout overrides the query timeout of the loaders. The timeout is disabled by 0.
+func WithQueryTimeout(timeout time.Duration) LoaderOption {
+	return func(settings *LoaderSettings) {
+		settings.QueryTimeout = timeout
+	}
+}
+
+// WithDuplicateKeys overrides the handling of the keys with more than one row.
+func WithDuplicateKeys(policy DuplicateKeyPolicy) LoaderOption {
+	return func(settings *LoaderSettings) {
+		settings.DuplicateKeys = policy
+	}
+}
@@ -0,0 +1,233 @@
+package sqlc_dataloader
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+const (
+	// DefaultRetryMaxAttempts is the number of the attempts of a query if RetryPolicy.MaxAttempts is not set.
+	DefaultRetryMaxAttempts = 3
+	// DefaultRetryBaseDelay is the delay before the first retry if RetryPolicy.BaseDelay is not set.
+	DefaultRetryBaseDelay = 50 * time.Millisecond
+	// DefaultRetryMaxDelay is the maximal delay between the retries if RetryPolicy.MaxDelay is not set.
+	DefaultRetryMaxDelay = time.Second
+)
+
+// RetryPolicy retries the failed queries of a batch.
+// The delay between the attempts grows exponentially from BaseDelay to MaxDelay with a random jitter.
+type RetryPolicy struct {
+	// MaxAttempts is the number of the attempts of a query including the first one.
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	// Retryable checks if the error is transient. It is IsRetryable if it is nil.
+	Retryable func(err error) bool
+	// RetryTimeouts retries the queries failed with QueryTimeoutError, e.g. while waiting for a connection of the pool.
+	// A slow query is likely to time out again, so the timeouts are not retried by default.
+	RetryTimeouts bool
+	// Budget limits the retries of all loaders sharing it. The retries are not limited if it is nil.
+	Budget *RetryBudget
+	// Metrics counts the retries. It is not counted if it is nil.
+	Metrics *RetryMetrics
+	// OnRetry is called before each retry and when the query fails after the retryable error.
+	OnRetry func(ctx context.Context, event RetryEvent)
+}
+
+// RetryOutcome is the decision made after a retryable error.
+type RetryOutcome string
+
+const (
+	// RetryScheduled means that the query is retried after the delay.
+	RetryScheduled RetryOutcome = "retry"
+	// RetryExhausted means that the query has failed MaxAttempts times.
+	RetryExhausted RetryOutcome = "exhausted"
+	// RetryBudgetExceeded means that the retry is rejected by the budget.
+	RetryBudgetExceeded RetryOutcome = "budget_exceeded"
+)
+
+// RetryEvent describes a retryable error of a query.
+type RetryEvent struct {
+	// Table is the table of the loader in the format schema.tablename.
+	Table string
+	// Attempt is the number of the failed attempt starting from 1.
+	Attempt int
+	// Delay is the delay before the next attempt. It is 0 if the query is not retried.
+	Delay   time.Duration
+	Err     error
+	Outcome RetryOutcome
+}
+
+// RetryBudget limits the retries to a ratio of the successful queries,
+// so the retries do not multiply the load of a database that is already failing.
+// It starts with MaxRetries tokens, each retry takes one token and each successful query returns Ratio tokens.
+type RetryBudget struct {
+	mu        sync.Mutex
+	tokens    float64
+	maxTokens float64
+	ratio     float64
+}
+
+func NewRetryBudget(maxRetries int, ratio float64) *RetryBudget {
+	return &RetryBudget{
+		tokens:    float64(maxRetries),
+		maxTokens: float64(maxRetries),
+		ratio:     ratio,
+	}
+}
+
+func (b *RetryBudget) withdraw() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func (b *RetryBudget) deposit() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = min(b.tokens+b.ratio, b.maxTokens)
+}
+
+// RetryMetrics counts the retries of the loaders sharing it.
+type RetryMetrics struct {
+	retries        atomic.Int64
+	exhausted      atomic.Int64
+	budgetExceeded atomic.Int64
+}
+
+// RetryStats are the counters of RetryMetrics.
+type RetryStats struct {
+	// Retries is the number of the retried queries.
+	Retries int64
+	// Exhausted is the number of the queries failed after all attempts.
+	Exhausted int64
+	// BudgetExceeded is the number of the retries rejected by the budget.
+	BudgetExceeded int64
+}
+
+func (m *RetryMetrics) Stats() RetryStats {
+	return RetryStats{
+		Retries:        m.retries.Load(),
+		Exhausted:      m.exhausted.Load(),
+		BudgetExceeded: m.budgetExceeded.Load(),
+	}
+}
+
+func (m *RetryMetrics) observe(outcome RetryOutcome) {
+	if m == nil {
+		return
+	}
+	switch outcome {
+	case RetryScheduled:
+		m.retries.Add(1)
+	case RetryExhausted:
+		m.exhausted.Add(1)
+	case RetryBudgetExceeded:
+		m.budgetExceeded.Add(1)
+	}
+}
+
+// WithRetryPolicy overrides the retry policy of the loaders. The retries are disabled by nil.
+func WithRetryPolicy(policy *RetryPolicy) LoaderOption {
+	return func(settings *LoaderSettings) {
+		settings.Retry = policy
+	}
+}
+
+// IsRetryable returns true for the transient errors of pgx and database/sql:
+// serialization failures, deadlocks and connection errors.
+// The exceeded query timeout and the canceled or expired context of the caller are not retryable.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, ErrNoRows) {
+		return false
+	}
+	var timeoutErr *QueryTimeoutError
+	if errors.As(err, &timeoutErr) {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	// pgconn.PgError of pgx and pq.Error of lib/pq.
+	var stateErr interface{ SQLState() string }
+	if errors.As(err, &stateErr) {
+		state := stateErr.SQLState()
+		// serialization_failure, deadlock_detected and the connection exceptions.
+		return state == "40001" || state == "40P01" || strings.HasPrefix(state, "08")
+	}
+	// The errors of pgconn that are returned before the query is sent.
+	var safeErr interface{ SafeToRetry() bool }
+	if errors.As(err, &safeErr) && safeErr.SafeToRetry() {
+		return true
+	}
+	if errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func (p *RetryPolicy) retryable(err error) bool {
+	var timeoutErr *QueryTimeoutError
+	if p.RetryTimeouts && errors.As(err, &timeoutErr) {
+		return true
+	}
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return IsRetryable(err)
+}
+
+func (p *RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts <= 0 {
+		return DefaultRetryMaxAttempts
+	}
+	return p.MaxAttempts
+}
+
+// delay returns the delay after the failed attempt: the exponential backoff with the jitter of up to a half of it.
+func (p *RetryPolicy) delay(attempt int) time.Duration {
+	base, maxDelay := p.BaseDelay, p.MaxDelay
+	if base <= 0 {
+		base = DefaultRetryBaseDelay
+	}
+	if maxDelay <= 0 {
+		maxDelay = DefaultRetryMaxDelay
+	}
+	delay := base
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	delay = min(delay, maxDelay)
+	return delay/2 + rand.N(delay/2+1)
+}
+
+func (p *RetryPolicy) notify(ctx context.Context, event RetryEvent) {
+	p.Metrics.observe(event.Outcome)
+	if p.OnRetry != nil {
+		p.OnRetry(ctx, event)
+	}
+}
@@ -0,0 +1,170 @@
+package sqlc_dataloader_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+
+	dl "github.com/debugger84/sqlc-dataloader"
+	"github.com/stretchr/testify/require"
+)
+
+type sqlStateError struct {
+	state string
+}
+
+func (e *sqlStateError) Error() string {
+	return "ERROR (SQLSTATE " + e.state + ")"
+}
+
+func (e *sqlStateError) SQLState() string {
+	return e.state
+}
+
+type safeToRetryError struct{}
+
+func (e *safeToRetryError) Error() string {
+	return "failed to connect"
+}
+
+func (e *safeToRetryError) SafeToRetry() bool {
+	return true
+}
+
+func TestIsRetryable(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		err       error
+		retryable bool
+	}{
+		{name: "serialization failure", err: &sqlStateError{state: "40001"}, retryable: true},
+		{name: "deadlock", err: fmt.Errorf("query: %w", &sqlStateError{state: "40P01"}), retryable: true},
+		{name: "connection failure", err: &sqlStateError{state: "08006"}, retryable: true},
+		{name: "unique violation", err: &sqlStateError{state: "23505"}},
+		{name: "safe to retry", err: &safeToRetryError{}, retryable: true},
+		{name: "connection reset", err: fmt.Errorf("read: %w", syscall.ECONNRESET), retryable: true},
+		{name: "bad connection", err: driver.ErrBadConn, retryable: true},
+		{name: "query timeout", err: &dl.QueryTimeoutError{Timeout: time.Second, Err: context.DeadlineExceeded}},
+		{name: "deadline of the caller", err: context.DeadlineExceeded},
+		{name: "canceled", err: context.Canceled},
+		{name: "no rows", err: dl.ErrNoRows},
+		{name: "other error", err: errors.New("syntax error")},
+	} {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.retryable, dl.IsRetryable(tt.err))
+		})
+	}
+}
+
+func TestTableLoader_Retry(t *testing.T) {
+	serializationErr := &sqlStateError{state: "40001"}
+	db := &fakeDB{
+		users:    []user{{ID: 1, Name: "John"}},
+		failures: []error{serializationErr, fmt.Errorf("read: %w", syscall.ECONNRESET)},
+	}
+	metrics := &dl.RetryMetrics{}
+	var events []dl.RetryEvent
+	policy := &dl.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		Metrics:     metrics,
+		OnRetry: func(_ context.Context, event dl.RetryEvent) {
+			events = append(events, event)
+		},
+	}
+	loader := newUserTableLoader(db, dl.WithRetryPolicy(policy))
+
+	u, err := loader.Load(context.Background(), 1)
+
+	require.NoError(t, err)
+	require.Equal(t, user{ID: 1, Name: "John"}, u)
+	require.Len(t, db.queries, 3)
+	require.Equal(t, dl.RetryStats{Retries: 2}, metrics.Stats())
+	require.Len(t, events, 2)
+	require.Equal(t, "public.users", events[0].Table)
+	require.Equal(t, 1, events[0].Attempt)
+	require.Equal(t, dl.RetryScheduled, events[0].Outcome)
+	require.ErrorIs(t, events[0].Err, serializationErr)
+	require.Equal(t, 2, events[1].Attempt)
+}
+
+func TestTableLoader_RetryExhausted(t *testing.T) {
+	serializationErr := &sqlStateError{state: "40001"}
+	db := &fakeDB{failures: []error{serializationErr, serializationErr, serializationErr}}
+	metrics := &dl.RetryMetrics{}
+	loader := newUserTableLoader(
+		db, dl.WithRetryPolicy(&dl.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, Metrics: metrics}),
+	)
+
+	_, err := loader.Load(context.Background(), 1)
+
+	require.ErrorIs(t, err, serializationErr)
+	require.Len(t, db.queries, 2)
+	require.Equal(t, dl.RetryStats{Retries: 1, Exhausted: 1}, metrics.Stats())
+}
+
+func TestTableLoader_RetryTimeouts(t *testing.T) {
+	timeoutErr := &dl.QueryTimeoutError{Timeout: time.Second, Err: context.DeadlineExceeded}
+	db := &fakeDB{
+		users:    []user{{ID: 1, Name: "John"}},
+		failures: []error{timeoutErr, timeoutErr},
+	}
+	policy := &dl.RetryPolicy{BaseDelay: time.Millisecond}
+	loader := newUserTableLoader(db, dl.WithRetryPolicy(policy))
+
+	_, err := loader.Load(context.Background(), 1)
+	require.ErrorIs(t, err, timeoutErr, "the timeouts are not retried by default")
+	require.Len(t, db.queries, 1)
+
+	policy.RetryTimeouts = true
+	loader = newUserTableLoader(db, dl.WithRetryPolicy(policy))
+
+	u, err := loader.Load(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, user{ID: 1, Name: "John"}, u)
+	require.Len(t, db.queries, 3)
+}
+
+func TestTableLoader_RetryBudget(t *testing.T) {
+	serializationErr := &sqlStateError{state: "40001"}
+	db := &fakeDB{
+		users:    []user{{ID: 1, Name: "John"}},
+		failures: []error{serializationErr, serializationErr},
+	}
+	metrics := &dl.RetryMetrics{}
+	loader := newUserTableLoader(
+		db, dl.WithRetryPolicy(
+			&dl.RetryPolicy{BaseDelay: time.Millisecond, Budget: dl.NewRetryBudget(1, 0.5), Metrics: metrics},
+		),
+	)
+
+	_, err := loader.Load(context.Background(), 1)
+	require.ErrorIs(t, err, serializationErr, "the budget allows only one retry")
+	require.Equal(t, dl.RetryStats{Retries: 1, BudgetExceeded: 1}, metrics.Stats())
+
+	for range 2 {
+		_, err = loader.Load(context.Background(), 1)
+		require.NoError(t, err)
+	}
+	db.failures = []error{serializationErr}
+	_, err = loader.Load(context.Background(), 1)
+	require.NoError(t, err, "the successful queries return the tokens to the budget")
+	require.Equal(t, dl.RetryStats{Retries: 2, BudgetExceeded: 1}, metrics.Stats())
+}
+
+func TestTableLoader_RetryNotRetryable(t *testing.T) {
+	db := &fakeDB{failures: []error{&sqlStateError{state: "23505"}}}
+	metrics := &dl.RetryMetrics{}
+	loader := newUserTableLoader(db, dl.WithRetryPolicy(&dl.RetryPolicy{Metrics: metrics}))
+
+	_, err := loader.Load(context.Background(), 1)
+
+	require.Error(t, err)
+	require.Len(t, db.queries, 1)
+	require.Equal(t, dl.RetryStats{}, metrics.Stats())
+}
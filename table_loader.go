@@ -0,0 +1,366 @@
+package sqlc_dataloader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+// DefaultQueryConcurrency is the number of the parallel queries of one batch
+// split by LoaderSettings.MaxKeysPerQuery if LoaderSettings.QueryConcurrency is not set.
+const DefaultQueryConcurrency = 4
+
+// Rows is the result of a query, e.g. pgx.Rows.
+type Rows interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+	Close()
+}
+
+// Scanner scans the current row of the query result.
+type Scanner interface {
+	Scan(dest ...any) error
+}
+
+// QueryFunc runs the query with the arguments.
+type QueryFunc func(ctx context.Context, query string, args ...any) (Rows, error)
+
+// TableLoaderConfig is the configuration of TableLoader.
+type TableLoaderConfig[K comparable, V any] struct {
+	// Table is the name of the table in the format schema.tablename. It is used in the retry events.
+	Table string
+	// Query selects the rows by the keys passed as the only argument, e.g. "SELECT id, name FROM authors WHERE id = ANY($1)".
+	Query string
+	// DB runs the query.
+	DB QueryFunc
+	// Scan scans the row to the item. It is not used if ScanColumn is set.
+	Scan func(row Scanner) (V, error)
+	// ScanColumn returns the pointer to the field of the item for the column name, or nil for the unknown columns.
+	// If it is set, the rows are scanned by the column names, so the extra and reordered columns are tolerated.
+	ScanColumn func(item *V, column string) any
+	// Columns returns the column names of the query result for ScanColumn.
+	// If it is nil, the Columns method of the rows is used, e.g. of *sql.Rows.
+	Columns func(rows Rows) ([]string, error)
+	// Key returns the key of the item.
+	Key func(item V) K
+	// Args returns the query argument of the keys. The keys are passed as is if it is nil.
+	Args func(keys []K) any
+	// Cache is the cache of the loaded items. The items are not cached if it is nil.
+	Cache dataloader.Cache[K, V]
+	// ExistsQuery selects the keys of the existing rows by the keys passed as the only argument,
+	// e.g. "SELECT id FROM authors WHERE id = ANY($1)". Exists loads the full rows if it is empty.
+	ExistsQuery string
+	// ExistsCache is the cache of the results of Exists. The results are not cached if it is nil.
+	ExistsCache dataloader.Cache[K, bool]
+	LoaderSettings
+
+	// scanKey scans the key selected by ExistsQuery. The key is scanned as is if it is nil.
+	// KeyedLoader sets it to encode the scanned keys.
+	scanKey func(row Scanner) (K, error)
+}
+
+// TableLoader batches the requests of the rows of one table by the keys.
+// The generated loaders wrap it with the types of the tables.
+type TableLoader[K comparable, V any] struct {
+	config      TableLoaderConfig[K, V]
+	innerLoader *dataloader.Loader[K, V]
+	// collect scans the rows of a query to the items by the keys.
+	collect func(ctx context.Context, rows Rows, keys []K) (*fetchedItems[K, V], error)
+	// missingErr is the error of the keys without rows. The zero value is returned for them if it is nil.
+	missingErr error
+	// exists checks the keys by ExistsQuery. It is nil if the query is not set.
+	exists *TableLoader[K, bool]
+}
+
+// NewTableLoader creates the loader. The options override the settings of the config.
+func NewTableLoader[K comparable, V any](config TableLoaderConfig[K, V], options ...LoaderOption) *TableLoader[K, V] {
+	l := newTableLoader(config, options...)
+	l.collect = l.collectItems
+	l.missingErr = ErrNoRows
+	if config.ExistsQuery != "" {
+		l.exists = newExistsLoader(l.config)
+	}
+	return l
+}
+
+// newTableLoader creates the loader without the collect function.
+func newTableLoader[K comparable, V any](config TableLoaderConfig[K, V], options ...LoaderOption) *TableLoader[K, V] {
+	for _, option := range options {
+		option(&config.LoaderSettings)
+	}
+	if config.Cache == nil {
+		config.Cache = &dataloader.NoCache[K, V]{}
+	}
+	if config.DuplicateKeys == "" {
+		config.DuplicateKeys = DuplicateKeysWarn
+	}
+	l := &TableLoader[K, V]{
+		config: config,
+	}
+	l.innerLoader = dataloader.NewBatchedLoader(l.batch, dataloader.WithCache(config.Cache))
+	return l
+}
+
+// Load loads the item by the key. It returns ErrNoRows if there is no row with the key.
+// The loaded key is added to the cache of Exists.
+func (l *TableLoader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	item, err := l.innerLoader.Load(ctx, key)()
+	if err == nil {
+		l.markExisting(ctx, key)
+	}
+	return item, err
+}
+
+// LoadMany loads the items by the keys. The errors are nil if all items are loaded,
+// otherwise they contain ErrNoRows for the missing keys.
+// The loaded keys are added to the cache of Exists.
+func (l *TableLoader[K, V]) LoadMany(ctx context.Context, keys []K) ([]V, []error) {
+	items, errs := l.innerLoader.LoadMany(ctx, keys)()
+	for i, key := range keys {
+		if errs == nil || errs[i] == nil {
+			l.markExisting(ctx, key)
+		}
+	}
+	return items, errs
+}
+
+// Clear removes the item and the result of Exists from the caches.
+func (l *TableLoader[K, V]) Clear(ctx context.Context, key K) {
+	l.innerLoader.Clear(ctx, key)
+	if l.exists != nil {
+		l.exists.Clear(ctx, key)
+	}
+}
+
+// Prime adds the item to the cache if there is no item with the key.
+// The key is added to the cache of Exists.
+func (l *TableLoader[K, V]) Prime(ctx context.Context, key K, value V) {
+	l.innerLoader.Prime(ctx, key, value)
+	l.markExisting(ctx, key)
+}
+
+// batch loads the items of the batch. The duplicate keys are queried once.
+func (l *TableLoader[K, V]) batch(ctx context.Context, keys []K) []*dataloader.Result[V] {
+	positions := make(map[K]int, len(keys))
+	unique := make([]K, 0, len(keys))
+	for _, key := range keys {
+		if _, ok := positions[key]; !ok {
+			positions[key] = len(unique)
+			unique = append(unique, key)
+		}
+	}
+	if len(unique) == len(keys) {
+		return l.batchUnique(ctx, keys)
+	}
+
+	uniqueResult := l.batchUnique(ctx, unique)
+	result := make([]*dataloader.Result[V], len(keys))
+	for i, key := range keys {
+		result[i] = uniqueResult[positions[key]]
+	}
+	return result
+}
+
+// batchUnique loads the items of the unique keys. The keys are split into the chunks of MaxKeysPerQuery keys,
+// the chunks are fetched in parallel and the error of a chunk is returned only for its keys.
+func (l *TableLoader[K, V]) batchUnique(ctx context.Context, keys []K) []*dataloader.Result[V] {
+	result := make([]*dataloader.Result[V], len(keys))
+	size := l.config.MaxKeysPerQuery
+	if size <= 0 || size >= len(keys) {
+		l.fillResults(ctx, keys, result)
+		return result
+	}
+
+	concurrency := l.config.QueryConcurrency
+	if concurrency <= 0 {
+		concurrency = DefaultQueryConcurrency
+	}
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for start := 0; start < len(keys); start += size {
+		end := min(start+size, len(keys))
+		semaphore <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			l.fillResults(ctx, keys[start:end], result[start:end])
+		}()
+	}
+	wg.Wait()
+	return result
+}
+
+// fillResults fetches the items by the keys and sets the results of the keys.
+func (l *TableLoader[K, V]) fillResults(ctx context.Context, keys []K, result []*dataloader.Result[V]) {
+	fetched, err := l.fetchWithRetry(ctx, keys)
+	for i, key := range keys {
+		if err != nil {
+			result[i] = &dataloader.Result[V]{Error: err}
+			continue
+		}
+		if rowCount, ok := fetched.duplicates[key]; ok && l.config.DuplicateKeys == DuplicateKeysError {
+			result[i] = &dataloader.Result[V]{Error: &DuplicateKeyError{Table: l.config.Table, Key: key, Rows: rowCount}}
+			continue
+		}
+		if item, ok := fetched.items[key]; ok {
+			result[i] = &dataloader.Result[V]{Data: item}
+		} else {
+			result[i] = &dataloader.Result[V]{Error: l.missingErr}
+		}
+	}
+}
+
+// fetchWithRetry fetches the items and retries the retryable errors by the retry policy.
+func (l *TableLoader[K, V]) fetchWithRetry(ctx context.Context, keys []K) (*fetchedItems[K, V], error) {
+	policy := l.config.Retry
+	if policy == nil {
+		return l.fetch(ctx, keys)
+	}
+	for attempt := 1; ; attempt++ {
+		fetched, err := l.fetch(ctx, keys)
+		if err == nil {
+			policy.Budget.deposit()
+			return fetched, nil
+		}
+		if ctx.Err() != nil || !policy.retryable(err) {
+			return nil, err
+		}
+		event := RetryEvent{Table: l.config.Table, Attempt: attempt, Err: err}
+		switch {
+		case attempt >= policy.maxAttempts():
+			event.Outcome = RetryExhausted
+		case !policy.Budget.withdraw():
+			event.Outcome = RetryBudgetExceeded
+		default:
+			event.Outcome = RetryScheduled
+			event.Delay = policy.delay(attempt)
+		}
+		policy.notify(ctx, event)
+		if event.Outcome != RetryScheduled {
+			return nil, err
+		}
+
+		timer := time.NewTimer(event.Delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		}
+	}
+}
+
+// fetch runs the query under the query timeout.
+// The QueryTimeoutError is returned if the timeout of the loader is exceeded, not the deadline of the caller.
+func (l *TableLoader[K, V]) fetch(ctx context.Context, keys []K) (*fetchedItems[K, V], error) {
+	timeout := l.config.QueryTimeout
+	if timeout <= 0 {
+		return l.query(ctx, keys)
+	}
+	queryCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	fetched, err := l.query(queryCtx, keys)
+	if err != nil && errors.Is(queryCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		return nil, &QueryTimeoutError{Timeout: timeout, Err: err}
+	}
+	return fetched, err
+}
+
+// fetchedItems are the items fetched by the keys.
+type fetchedItems[K comparable, V any] struct {
+	// items are the first rows of the keys.
+	items map[K]V
+	// duplicates are the numbers of the rows of the keys with more than one row.
+	duplicates map[K]int
+}
+
+// query runs the query and collects the rows.
+func (l *TableLoader[K, V]) query(ctx context.Context, keys []K) (*fetchedItems[K, V], error) {
+	var args any = keys
+	if l.config.Args != nil {
+		args = l.config.Args(keys)
+	}
+	rows, err := l.config.DB(ctx, l.config.Query, args)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return l.collect(ctx, rows, keys)
+}
+
+// collectItems scans the rows to the items. The first row of a key is kept, the next ones are counted as duplicates.
+func (l *TableLoader[K, V]) collectItems(ctx context.Context, rows Rows, keys []K) (*fetchedItems[K, V], error) {
+	var err error
+	scan := l.config.Scan
+	if l.config.ScanColumn != nil {
+		if scan, err = l.columnScanner(rows); err != nil {
+			return nil, err
+		}
+	}
+
+	fetched := &fetchedItems[K, V]{items: make(map[K]V, len(keys))}
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		key := l.config.Key(item)
+		if _, ok := fetched.items[key]; !ok {
+			fetched.items[key] = item
+			continue
+		}
+		if fetched.duplicates == nil {
+			fetched.duplicates = map[K]int{}
+		}
+		fetched.duplicates[key] = max(fetched.duplicates[key], 1) + 1
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if l.config.DuplicateKeys == DuplicateKeysWarn {
+		for key, rowCount := range fetched.duplicates {
+			slog.WarnContext(
+				ctx, "the loader has found more than one row per key, the first row is used",
+				"table", l.config.Table, "key", key, "rows", rowCount,
+			)
+		}
+	}
+	return fetched, nil
+}
+
+// columnScanner returns the function scanning the rows by the column names of the query result.
+// The values of the unknown columns are discarded, the fields of the missing columns keep the zero values.
+func (l *TableLoader[K, V]) columnScanner(rows Rows) (func(row Scanner) (V, error), error) {
+	var columns []string
+	var err error
+	if l.config.Columns != nil {
+		columns, err = l.config.Columns(rows)
+	} else if columnRows, ok := rows.(interface{ Columns() ([]string, error) }); ok {
+		columns, err = columnRows.Columns()
+	} else {
+		err = fmt.Errorf("the column names of %T are unknown, set the Columns function of the loader config", rows)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return func(row Scanner) (V, error) {
+		var item V
+		dest := make([]any, len(columns))
+		for i, column := range columns {
+			if dest[i] = l.config.ScanColumn(&item, column); dest[i] == nil {
+				dest[i] = new(any)
+			}
+		}
+		err := row.Scan(dest...)
+		return item, err
+	}, nil
+}
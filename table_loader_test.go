@@ -0,0 +1,330 @@
+package sqlc_dataloader_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	dl "github.com/debugger84/sqlc-dataloader"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRows struct {
+	rows   []user
+	next   int
+	closed bool
+}
+
+func (r *fakeRows) Next() bool {
+	r.next++
+	return r.next <= len(r.rows)
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	row := r.rows[r.next-1]
+	*dest[0].(*int) = row.ID
+	*dest[1].(*string) = row.Name
+	return nil
+}
+
+func (r *fakeRows) Err() error {
+	return nil
+}
+
+func (r *fakeRows) Close() {
+	r.closed = true
+}
+
+type fakeDB struct {
+	users   []user
+	queries [][]int
+	rows    []*fakeRows
+	err     error
+	// failKey fails the queries with the key.
+	failKey int
+	// failures are returned by the next queries one by one.
+	failures []error
+	// delay is the duration of a query used to check the parallel queries.
+	delay       time.Duration
+	running     int
+	maxParallel int
+	mu          sync.Mutex
+}
+
+func (db *fakeDB) Query(ctx context.Context, _ string, args ...any) (dl.Rows, error) {
+	db.mu.Lock()
+	db.running++
+	db.maxParallel = max(db.maxParallel, db.running)
+	db.mu.Unlock()
+	var ctxErr error
+	select {
+	case <-time.After(db.delay):
+	case <-ctx.Done():
+		ctxErr = ctx.Err()
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.running--
+	if ctxErr != nil {
+		return nil, ctxErr
+	}
+	if db.err != nil {
+		return nil, db.err
+	}
+	keys := args[0].([]int)
+	db.queries = append(db.queries, keys)
+	if len(db.failures) > 0 {
+		err := db.failures[0]
+		db.failures = db.failures[1:]
+		return nil, err
+	}
+	if db.failKey != 0 && slices.Contains(keys, db.failKey) {
+		return nil, errors.New("query failed")
+	}
+	rows := &fakeRows{}
+	for _, u := range db.users {
+		for _, key := range keys {
+			if u.ID == key {
+				rows.rows = append(rows.rows, u)
+			}
+		}
+	}
+	db.rows = append(db.rows, rows)
+	return rows, nil
+}
+
+func newUserTableLoader(db *fakeDB, options ...dl.LoaderOption) *dl.TableLoader[int, user] {
+	config := dl.TableLoaderConfig[int, user]{
+		Table: "public.users",
+		Query: "SELECT id, name FROM users WHERE id = ANY($1)",
+		DB:    db.Query,
+		Scan: func(row dl.Scanner) (user, error) {
+			var u user
+			err := row.Scan(&u.ID, &u.Name)
+			return u, err
+		},
+		Key: func(u user) int {
+			return u.ID
+		},
+	}
+	return dl.NewTableLoader(config, options...)
+}
+
+func TestTableLoader_LoadMany(t *testing.T) {
+	db := &fakeDB{users: []user{{ID: 1, Name: "John"}, {ID: 3, Name: "Jane"}}}
+	loader := newUserTableLoader(db)
+
+	users, errs := loader.LoadMany(context.Background(), []int{1, 2, 3})
+
+	require.Equal(t, []user{{ID: 1, Name: "John"}, {}, {ID: 3, Name: "Jane"}}, users)
+	require.Len(t, errs, 3)
+	require.NoError(t, errs[0])
+	require.ErrorIs(t, errs[1], dl.ErrNoRows)
+	require.NoError(t, errs[2])
+	require.Len(t, db.queries, 1)
+	require.ElementsMatch(t, []int{1, 2, 3}, db.queries[0])
+	require.True(t, db.rows[0].closed)
+}
+
+func TestTableLoader_Load(t *testing.T) {
+	db := &fakeDB{users: []user{{ID: 1, Name: "John"}}}
+	loader := newUserTableLoader(db)
+	loader.Prime(context.Background(), 2, user{ID: 2, Name: "Primed"})
+
+	u, err := loader.Load(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, user{ID: 1, Name: "John"}, u)
+
+	_, err = loader.Load(context.Background(), 2)
+	require.ErrorIs(t, err, dl.ErrNoRows, "the loader without the cache ignores the primed items")
+
+	db.err = errors.New("connection refused")
+	_, err = loader.Load(context.Background(), 1)
+	require.EqualError(t, err, "connection refused")
+}
+
+func TestTableLoader_MaxKeysPerQuery(t *testing.T) {
+	db := &fakeDB{
+		users:   []user{{ID: 1, Name: "John"}, {ID: 2, Name: "Jane"}, {ID: 5, Name: "Jack"}},
+		failKey: 4,
+		delay:   10 * time.Millisecond,
+	}
+	loader := newUserTableLoader(
+		db, func(settings *dl.LoaderSettings) {
+			settings.MaxKeysPerQuery = 2
+			settings.QueryConcurrency = 2
+		},
+	)
+	keys := []int{1, 2, 3, 4, 5, 6, 7}
+
+	users, errs := loader.LoadMany(context.Background(), keys)
+
+	require.Len(t, db.queries, 4)
+	var queried []int
+	var failedKeys []int
+	for _, query := range db.queries {
+		require.LessOrEqual(t, len(query), 2)
+		queried = append(queried, query...)
+		if slices.Contains(query, db.failKey) {
+			failedKeys = query
+		}
+	}
+	require.ElementsMatch(t, keys, queried)
+	require.Equal(t, 2, db.maxParallel)
+
+	require.Len(t, errs, len(keys))
+	for i, key := range keys {
+		switch {
+		case slices.Contains(failedKeys, key):
+			require.EqualError(t, errs[i], "query failed", "the error of a chunk is returned only for its keys")
+		case key == 1 || key == 2 || key == 5:
+			require.NoError(t, errs[i])
+			require.Equal(t, key, users[i].ID)
+		default:
+			require.ErrorIs(t, errs[i], dl.ErrNoRows)
+		}
+	}
+}
+
+func TestTableLoader_QueryTimeout(t *testing.T) {
+	db := &fakeDB{users: []user{{ID: 1, Name: "John"}}, delay: 50 * time.Millisecond}
+	loader := newUserTableLoader(db, dl.WithQueryTimeout(5*time.Millisecond))
+
+	_, err := loader.Load(context.Background(), 1)
+	var timeoutErr *dl.QueryTimeoutError
+	require.ErrorAs(t, err, &timeoutErr)
+	require.Equal(t, 5*time.Millisecond, timeoutErr.Timeout)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.NotErrorIs(t, err, dl.ErrNoRows)
+
+	loader = newUserTableLoader(db, dl.WithQueryTimeout(time.Second))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	_, err = loader.Load(ctx, 1)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.False(t, errors.As(err, &timeoutErr), "the deadline of the caller is not the timeout of the loader")
+
+	loader = newUserTableLoader(db, dl.WithQueryTimeout(5*time.Millisecond), dl.WithQueryTimeout(0))
+	u, err := loader.Load(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, user{ID: 1, Name: "John"}, u)
+}
+
+func TestTableLoader_DuplicateKeys(t *testing.T) {
+	users := []user{{ID: 1, Name: "John"}, {ID: 1, Name: "Johnny"}, {ID: 1, Name: "Jack"}, {ID: 2, Name: "Jane"}}
+
+	loader := newUserTableLoader(&fakeDB{users: users}, dl.WithDuplicateKeys(dl.DuplicateKeysError))
+	result, errs := loader.LoadMany(context.Background(), []int{1, 2})
+	var duplicateErr *dl.DuplicateKeyError
+	require.ErrorAs(t, errs[0], &duplicateErr)
+	require.Equal(t, dl.DuplicateKeyError{Table: "public.users", Key: 1, Rows: 3}, *duplicateErr)
+	require.EqualError(t, errs[0], "the key 1 of public.users has 3 rows, expected one")
+	require.NoError(t, errs[1])
+	require.Equal(t, user{ID: 2, Name: "Jane"}, result[1])
+
+	for _, policy := range []dl.DuplicateKeyPolicy{dl.DuplicateKeysFirst, dl.DuplicateKeysWarn, ""} {
+		loader = newUserTableLoader(&fakeDB{users: users}, dl.WithDuplicateKeys(policy))
+		u, err := loader.Load(context.Background(), 1)
+		require.NoError(t, err)
+		require.Equal(t, user{ID: 1, Name: "John"}, u, "the first row is returned by the %q policy", policy)
+	}
+}
+
+func TestTableLoader_DuplicateKeysWarning(t *testing.T) {
+	var logs bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	defer slog.SetDefault(defaultLogger)
+
+	db := &fakeDB{users: []user{{ID: 1, Name: "John"}, {ID: 1, Name: "Johnny"}}}
+	_, err := newUserTableLoader(db).Load(context.Background(), 1)
+	require.NoError(t, err)
+	require.Contains(t, logs.String(), "the loader has found more than one row per key")
+	require.Contains(t, logs.String(), "table=public.users key=1 rows=2")
+
+	logs.Reset()
+	_, err = newUserTableLoader(db, dl.WithDuplicateKeys(dl.DuplicateKeysFirst)).Load(context.Background(), 1)
+	require.NoError(t, err)
+	require.Empty(t, logs.String())
+}
+
+// columnRows are the rows with the column names like *sql.Rows.
+type columnRows struct {
+	columns []string
+	values  [][]any
+	next    int
+}
+
+func (r *columnRows) Next() bool {
+	r.next++
+	return r.next <= len(r.values)
+}
+
+func (r *columnRows) Scan(dest ...any) error {
+	if len(dest) != len(r.columns) {
+		return fmt.Errorf("expected %d destinations, got %d", len(r.columns), len(dest))
+	}
+	for i, value := range r.values[r.next-1] {
+		switch d := dest[i].(type) {
+		case *int:
+			*d = value.(int)
+		case *string:
+			*d = value.(string)
+		case *any:
+			*d = value
+		default:
+			return fmt.Errorf("unexpected destination %T", dest[i])
+		}
+	}
+	return nil
+}
+
+func (r *columnRows) Err() error {
+	return nil
+}
+
+func (r *columnRows) Close() {}
+
+func (r *columnRows) Columns() ([]string, error) {
+	return r.columns, nil
+}
+
+func TestTableLoader_ScanColumn(t *testing.T) {
+	rows := &columnRows{
+		columns: []string{"name", "created_at", "id"},
+		values:  [][]any{{"John", "2024-01-01", 1}, {"Jane", "2024-01-02", 2}},
+	}
+	loader := dl.NewTableLoader(
+		dl.TableLoaderConfig[int, user]{
+			Table: "public.users",
+			Query: "SELECT * FROM users WHERE id = ANY($1)",
+			DB: func(_ context.Context, _ string, _ ...any) (dl.Rows, error) {
+				return rows, nil
+			},
+			ScanColumn: func(item *user, column string) any {
+				switch column {
+				case "id":
+					return &item.ID
+				case "name":
+					return &item.Name
+				}
+				return nil
+			},
+			Key: func(u user) int {
+				return u.ID
+			},
+		},
+	)
+
+	users, errs := loader.LoadMany(context.Background(), []int{1, 2})
+
+	require.Nil(t, errs, "the reordered and unknown columns are tolerated")
+	require.Equal(t, []user{{ID: 1, Name: "John"}, {ID: 2, Name: "Jane"}}, users)
+}